@@ -0,0 +1,313 @@
+// Package analyzer defines an Analyzer that reports the direct use of the
+// time package functions and the context deadlines in packages which import
+// github.com/tilinna/clock, as these are not controlled by a Mock.
+//
+// Where a context.Context named ctx is in scope, the diagnostics include
+// suggested fixes which replace the calls with the context wrappers of the
+// clock package, such as clock.Now(ctx) for time.Now(). The context
+// deadlines are replaced with clock.TimeoutContext and clock.DeadlineContext,
+// which use the Clock of the parent context. The calls of time.NewTimer,
+// time.NewTicker and time.AfterFunc are only fixed if their results can
+// become a *clock.Timer or *clock.Ticker. See ResultReplaceable.
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+	"strconv"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// ClockPath is the import path of the clock package.
+const ClockPath = "github.com/tilinna/clock"
+
+// CtxName is the name of the context variable used by the suggested fixes.
+const CtxName = "ctx"
+
+// Analyzer reports the direct use of the time and context functions which
+// have an equivalent in the clock package.
+var Analyzer = &analysis.Analyzer{
+	Name: "clock",
+	Doc:  "report time and context calls that bypass github.com/tilinna/clock",
+	Run:  run,
+}
+
+// Replacement describes the clock package equivalent of a function.
+type Replacement struct {
+	// Name is the name of the function in the clock package.
+	Name string
+
+	// NeedsCtx is true if the context is inserted as the first argument.
+	// Otherwise the first argument is already the parent context.
+	NeedsCtx bool
+}
+
+// Replacements maps the package paths and function names to their clock
+// package equivalents.
+var Replacements = map[string]map[string]Replacement{
+	"time": {
+		"After":     {"After", true},
+		"AfterFunc": {"AfterFunc", true},
+		"NewTicker": {"NewTicker", true},
+		"NewTimer":  {"NewTimer", true},
+		"Now":       {"Now", true},
+		"Since":     {"Since", true},
+		"Sleep":     {"Sleep", true},
+		"Tick":      {"Tick", true},
+		"Until":     {"Until", true},
+	},
+	"context": {
+		"WithDeadline": {"DeadlineContext", false},
+		"WithTimeout":  {"TimeoutContext", false},
+	},
+}
+
+// Lookup returns the clock package equivalent of the function fn, if any.
+func Lookup(fn *types.Func) (Replacement, bool) {
+	if fn.Pkg() == nil {
+		return Replacement{}, false
+	}
+	if sig, ok := fn.Type().(*types.Signature); !ok || sig.Recv() != nil {
+		return Replacement{}, false
+	}
+	r, ok := Replacements[fn.Pkg().Path()][fn.Name()]
+	return r, ok
+}
+
+// Callee returns the package level function called by call, if any.
+func Callee(info *types.Info, call *ast.CallExpr) (*types.Func, *ast.SelectorExpr) {
+	sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+	if !ok {
+		return nil, nil
+	}
+	fn, ok := info.Uses[sel.Sel].(*types.Func)
+	if !ok {
+		return nil, nil
+	}
+	return fn, sel
+}
+
+// ImportName returns the name under which the file imports path, or false
+// if it does not.
+func ImportName(file *ast.File, path string) (string, bool) {
+	for _, imp := range file.Imports {
+		if p, err := strconv.Unquote(imp.Path.Value); err != nil || p != path {
+			continue
+		}
+		if imp.Name == nil {
+			return "clock", true
+		}
+		if imp.Name.Name == "_" || imp.Name.Name == "." {
+			return "", false
+		}
+		return imp.Name.Name, true
+	}
+	return "", false
+}
+
+func importsClock(pkg *types.Package) bool {
+	for _, imp := range pkg.Imports() {
+		if imp.Path() == ClockPath {
+			return true
+		}
+	}
+	return false
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	if pass.Pkg.Path() == ClockPath || !importsClock(pass.Pkg) {
+		return nil, nil
+	}
+	for _, file := range pass.Files {
+		clockName, imported := ImportName(file, ClockPath)
+		var stack []ast.Node
+		ast.Inspect(file, func(n ast.Node) bool {
+			if n == nil {
+				stack = stack[:len(stack)-1]
+				return true
+			}
+			stack = append(stack, n)
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			fn, sel := Callee(pass.TypesInfo, call)
+			if fn == nil {
+				return true
+			}
+			r, ok := Lookup(fn)
+			if !ok {
+				return true
+			}
+			d := analysis.Diagnostic{
+				Pos:     call.Pos(),
+				End:     call.End(),
+				Message: fn.Pkg().Name() + "." + fn.Name() + " bypasses the Clock, use clock." + r.Name,
+			}
+			if imported && (!r.NeedsCtx || CtxInScope(pass.TypesInfo, file, call.Pos(), CtxName)) &&
+				ResultReplaceable(pass.TypesInfo, stack, fn) {
+				d.SuggestedFixes = []analysis.SuggestedFix{{
+					Message:   "Replace with " + clockName + "." + r.Name,
+					TextEdits: Edits(call, sel, clockName, r, CtxName),
+				}}
+			}
+			pass.Report(d)
+			return true
+		})
+	}
+	return nil, nil
+}
+
+// Edits returns the edits replacing call of sel with the Replacement r.
+func Edits(call *ast.CallExpr, sel *ast.SelectorExpr, clockName string, r Replacement, ctx string) []analysis.TextEdit {
+	edits := []analysis.TextEdit{{
+		Pos:     sel.Pos(),
+		End:     sel.End(),
+		NewText: []byte(clockName + "." + r.Name),
+	}}
+	if r.NeedsCtx {
+		arg := ctx
+		if len(call.Args) > 0 {
+			arg += ", "
+		}
+		edits = append(edits, analysis.TextEdit{
+			Pos:     call.Lparen + 1,
+			End:     call.Lparen + 1,
+			NewText: []byte(arg),
+		})
+	}
+	return edits
+}
+
+// CtxInScope reports whether a context.Context variable called name is in
+// scope at pos.
+func CtxInScope(info *types.Info, file *ast.File, pos token.Pos, name string) bool {
+	scope := info.Scopes[file]
+	if scope == nil {
+		return false
+	}
+	_, obj := scope.Innermost(pos).LookupParent(name, pos)
+	v, ok := obj.(*types.Var)
+	return ok && IsContext(v.Type())
+}
+
+// IsContext reports whether t is context.Context.
+func IsContext(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == "context" && obj.Name() == "Context"
+}
+
+// commonSelectors maps the names of the time types, whose clock package
+// equivalents return the clock type of the same name, to the fields and
+// methods the two types have in common.
+var commonSelectors = map[string][]string{
+	"Timer":  {"C", "Reset", "Stop"},
+	"Ticker": {"C", "Stop"},
+}
+
+// ResultReplaceable reports whether the result of a call of fn can take the
+// type returned by its clock package equivalent, such as *clock.Timer for
+// time.NewTimer. Unless the types are the same, the result must be
+// discarded, or declared by := or var as a variable which is only used to
+// select the fields and methods common to both types.
+//
+// The stack holds the nodes enclosing the call, outermost first, and ends
+// with the call. The uses of the variable are searched in stack[0].
+func ResultReplaceable(info *types.Info, stack []ast.Node, fn *types.Func) bool {
+	common, ok := commonSelectors[resultName(fn)]
+	if !ok {
+		return true
+	}
+	i := len(stack) - 2
+	for i >= 0 {
+		if _, ok := stack[i].(*ast.ParenExpr); !ok {
+			break
+		}
+		i--
+	}
+	if i < 0 {
+		return false
+	}
+	expr, _ := stack[i+1].(ast.Expr)
+	var id *ast.Ident
+	switch p := stack[i].(type) {
+	case *ast.ExprStmt:
+		return true
+	case *ast.AssignStmt:
+		j := slices.Index(p.Rhs, expr)
+		if j < 0 || len(p.Lhs) != len(p.Rhs) {
+			return false
+		}
+		if id, _ = p.Lhs[j].(*ast.Ident); id == nil {
+			return false
+		}
+		if id.Name == "_" {
+			return true
+		}
+		if p.Tok != token.DEFINE {
+			return false
+		}
+	case *ast.ValueSpec:
+		j := slices.Index(p.Values, expr)
+		if j < 0 || p.Type != nil || len(p.Names) != len(p.Values) {
+			return false
+		}
+		if id = p.Names[j]; id.Name == "_" {
+			return true
+		}
+	default:
+		return false
+	}
+	v, ok := info.Defs[id].(*types.Var)
+	return ok && onlySelects(info, stack[0], v, common)
+}
+
+// resultName returns the name of the time type to which fn returns a
+// pointer, or "" if it does not.
+func resultName(fn *types.Func) string {
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok || sig.Results().Len() != 1 {
+		return ""
+	}
+	ptr, ok := sig.Results().At(0).Type().(*types.Pointer)
+	if !ok {
+		return ""
+	}
+	named, ok := ptr.Elem().(*types.Named)
+	if !ok {
+		return ""
+	}
+	obj := named.Obj()
+	if obj.Pkg() == nil || obj.Pkg().Path() != "time" {
+		return ""
+	}
+	return obj.Name()
+}
+
+// onlySelects reports whether all uses of v within root select one of names.
+func onlySelects(info *types.Info, root ast.Node, v *types.Var, names []string) bool {
+	selected := make(map[*ast.Ident]bool)
+	ast.Inspect(root, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if x, ok := sel.X.(*ast.Ident); ok && info.Uses[x] == v && slices.Contains(names, sel.Sel.Name) {
+			selected[x] = true
+		}
+		return true
+	})
+	for id, obj := range info.Uses {
+		if obj == v && !selected[id] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,104 @@
+// Command clockify rewrites the time and context calls of Go packages to use
+// github.com/tilinna/clock.
+//
+// Usage:
+//
+//	clockify [-w] [-l] [-ctx name] [-tests=false] packages...
+//
+// Within the methods of a type with a clock.Clock field, the calls are
+// rewritten into method calls on the field, such as c.clock.Now() for
+// time.Now(). Elsewhere, they are rewritten into the context wrappers of the
+// clock package if a context.Context variable is in scope, such as
+// clock.Now(ctx). The context.WithTimeout and context.WithDeadline calls are
+// always rewritten, into clock.TimeoutContext and clock.DeadlineContext.
+//
+// The calls of time.NewTimer, time.NewTicker and time.AfterFunc are left
+// alone if their results must keep the time types, for example when stored
+// in a *time.Timer field. These and the other calls which cannot be
+// rewritten are reported. By default, the rewritten files are printed to
+// standard output.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+
+	"github.com/tilinna/clock/analyzer"
+	"golang.org/x/tools/go/packages"
+)
+
+var (
+	write = flag.Bool("w", false, "write the result to the source files instead of standard output")
+	list  = flag.Bool("l", false, "list the files which would be rewritten")
+	ctx   = flag.String("ctx", analyzer.CtxName, "the name of the context.Context variable to use")
+	tests = flag.Bool("tests", true, "rewrite the test files too")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: clockify [flags] packages...")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, "clockify:", err)
+		os.Exit(1)
+	}
+}
+
+func run(patterns []string) error {
+	cfg := &packages.Config{
+		Mode:  packages.NeedName | packages.NeedFiles | packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo,
+		Tests: *tests,
+	}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return err
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		return fmt.Errorf("failed to load packages")
+	}
+	done := map[string]bool{}
+	for _, pkg := range pkgs {
+		if pkg.PkgPath == analyzer.ClockPath {
+			continue
+		}
+		r := &rewriter{fset: pkg.Fset, info: pkg.TypesInfo, ctx: *ctx}
+		for _, file := range pkg.Syntax {
+			name := pkg.Fset.File(file.Pos()).Name()
+			// A file is loaded again with the test variant of its package.
+			if done[name] {
+				continue
+			}
+			done[name] = true
+			if !r.rewrite(file) {
+				continue
+			}
+			var buf bytes.Buffer
+			if err := format.Node(&buf, pkg.Fset, file); err != nil {
+				return fmt.Errorf("%s: %v", name, err)
+			}
+			if *list {
+				fmt.Println(name)
+			}
+			if *write {
+				if err := os.WriteFile(name, buf.Bytes(), 0o666); err != nil {
+					return err
+				}
+			} else if !*list {
+				os.Stdout.Write(buf.Bytes())
+			}
+		}
+		for _, p := range r.problems {
+			fmt.Fprintln(os.Stderr, p)
+		}
+	}
+	return nil
+}
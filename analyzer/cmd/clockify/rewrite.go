@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"github.com/tilinna/clock/analyzer"
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// rewriter rewrites the time and context calls of a file into the clock
+// package equivalents.
+type rewriter struct {
+	fset *token.FileSet
+	info *types.Info
+	ctx  string // the name of the context variable
+
+	problems []string
+}
+
+// rewrite rewrites the calls of file in place, and reports whether the file
+// was changed. Within the methods of a type with a clock.Clock field, the
+// calls are rewritten into method calls on the field. Elsewhere, they are
+// rewritten into the context wrappers if a context.Context variable is in
+// scope. The calls which cannot be rewritten, including those whose result
+// must keep its time type, are added to r.problems.
+// See analyzer.ResultReplaceable.
+func (r *rewriter) rewrite(file *ast.File) bool {
+	clockName, imported := analyzer.ImportName(file, analyzer.ClockPath)
+	if !imported {
+		clockName = "clock"
+	}
+	changed, usesClock := false, false
+	for _, decl := range file.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok || fd.Body == nil {
+			continue
+		}
+		field := r.clockField(fd)
+		var stack []ast.Node
+		ast.Inspect(fd.Body, func(n ast.Node) bool {
+			if n == nil {
+				stack = stack[:len(stack)-1]
+				return true
+			}
+			stack = append(stack, n)
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			fn, sel := analyzer.Callee(r.info, call)
+			if fn == nil {
+				return true
+			}
+			repl, ok := analyzer.Lookup(fn)
+			if !ok {
+				return true
+			}
+			switch {
+			case !analyzer.ResultReplaceable(r.info, stack, fn):
+				r.problems = append(r.problems, fmt.Sprintf("%s: cannot rewrite %s.%s: the result is used as a %s",
+					r.fset.Position(sel.Pos()), fn.Pkg().Name(), fn.Name(), fn.Type().(*types.Signature).Results().At(0).Type()))
+				return true
+			case field != nil:
+				call.Fun = &ast.SelectorExpr{X: field, Sel: ast.NewIdent(method(fn))}
+			case !repl.NeedsCtx || analyzer.CtxInScope(r.info, file, call.Pos(), r.ctx):
+				call.Fun = &ast.SelectorExpr{X: ast.NewIdent(clockName), Sel: ast.NewIdent(repl.Name)}
+				if repl.NeedsCtx {
+					call.Args = append([]ast.Expr{ast.NewIdent(r.ctx)}, call.Args...)
+				}
+				usesClock = true
+			default:
+				r.problems = append(r.problems, fmt.Sprintf("%s: cannot rewrite %s.%s: no Clock field or %s in scope",
+					r.fset.Position(sel.Pos()), fn.Pkg().Name(), fn.Name(), r.ctx))
+				return true
+			}
+			changed = true
+			return true
+		})
+	}
+	if !changed {
+		return false
+	}
+	if usesClock && !imported {
+		astutil.AddImport(r.fset, file, analyzer.ClockPath)
+	}
+	for _, path := range []string{"time", "context"} {
+		if !astutil.UsesImport(file, path) {
+			astutil.DeleteImport(r.fset, file, path)
+		}
+	}
+	return true
+}
+
+// method returns the Clock method equivalent of fn.
+func method(fn *types.Func) string {
+	if r, ok := analyzer.Lookup(fn); ok && !r.NeedsCtx {
+		return r.Name
+	}
+	return fn.Name()
+}
+
+// clockField returns the expression selecting the clock.Clock field of the
+// receiver of fd, or nil if there is none.
+func (r *rewriter) clockField(fd *ast.FuncDecl) ast.Expr {
+	if fd.Recv == nil || len(fd.Recv.List) != 1 || len(fd.Recv.List[0].Names) != 1 {
+		return nil
+	}
+	recv := fd.Recv.List[0].Names[0]
+	if recv.Name == "_" {
+		return nil
+	}
+	obj, ok := r.info.Defs[recv].(*types.Var)
+	if !ok {
+		return nil
+	}
+	t := obj.Type()
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	st, ok := t.Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+	for i := 0; i < st.NumFields(); i++ {
+		f := st.Field(i)
+		if isClock(f.Type()) {
+			return &ast.SelectorExpr{X: ast.NewIdent(recv.Name), Sel: ast.NewIdent(f.Name())}
+		}
+	}
+	return nil
+}
+
+func isClock(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == analyzer.ClockPath && obj.Name() == "Clock"
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+)
+
+const clockSrc = `package clock
+
+import (
+	"context"
+	"time"
+)
+
+type Clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+	TimeoutContext(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc)
+}
+
+type Timer struct {
+	C <-chan time.Time
+}
+
+func (t *Timer) Stop() bool { return false }
+
+func NewTimer(ctx context.Context, d time.Duration) *Timer { return nil }
+func Now(ctx context.Context) time.Time { return time.Time{} }
+func Sleep(ctx context.Context, d time.Duration) {}
+func DeadlineContext(ctx context.Context, d time.Time) (context.Context, context.CancelFunc) { return ctx, nil }
+`
+
+const src = `package p
+
+import (
+	"context"
+	"time"
+
+	"github.com/tilinna/clock"
+)
+
+type server struct {
+	clock clock.Clock
+}
+
+func (s *server) run(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	time.Sleep(time.Second)
+	go func() {
+		_ = time.Now()
+	}()
+}
+
+func run(ctx context.Context) time.Time {
+	time.Sleep(time.Second)
+	return time.Now()
+}
+
+func deadline(parent context.Context) {
+	_, cancel := context.WithDeadline(parent, time.Now())
+	cancel()
+}
+
+type timers struct {
+	t *time.Timer
+}
+
+func (x *timers) reset(ctx context.Context) {
+	x.t = time.NewTimer(time.Second)
+	t := time.NewTimer(time.Second)
+	t.Stop()
+}
+`
+
+const want = `package p
+
+import (
+	"context"
+	"time"
+
+	"github.com/tilinna/clock"
+)
+
+type server struct {
+	clock clock.Clock
+}
+
+func (s *server) run(ctx context.Context) {
+	ctx, cancel := s.clock.TimeoutContext(ctx, time.Second)
+	defer cancel()
+	s.clock.Sleep(time.Second)
+	go func() {
+		_ = s.clock.Now()
+	}()
+}
+
+func run(ctx context.Context) time.Time {
+	clock.Sleep(ctx, time.Second)
+	return clock.Now(ctx)
+}
+
+func deadline(parent context.Context) {
+	_, cancel := clock.DeadlineContext(parent, time.Now())
+	cancel()
+}
+
+type timers struct {
+	t *time.Timer
+}
+
+func (x *timers) reset(ctx context.Context) {
+	x.t = time.NewTimer(time.Second)
+	t := clock.NewTimer(ctx, time.Second)
+	t.Stop()
+}
+`
+
+type testImporter struct {
+	pkgs map[string]*types.Package
+	std  types.Importer
+}
+
+func (imp testImporter) Import(path string) (*types.Package, error) {
+	if pkg, ok := imp.pkgs[path]; ok {
+		return pkg, nil
+	}
+	return imp.std.Import(path)
+}
+
+func check(t *testing.T, fset *token.FileSet, imp types.Importer, path, src string) (*ast.File, *types.Package, *types.Info) {
+	t.Helper()
+	file, err := parser.ParseFile(fset, path+".go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := &types.Info{
+		Uses:   map[*ast.Ident]types.Object{},
+		Defs:   map[*ast.Ident]types.Object{},
+		Scopes: map[ast.Node]*types.Scope{},
+	}
+	conf := types.Config{Importer: imp}
+	pkg, err := conf.Check(path, fset, []*ast.File{file}, info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return file, pkg, info
+}
+
+func TestRewrite(t *testing.T) {
+	fset := token.NewFileSet()
+	imp := testImporter{map[string]*types.Package{}, importer.Default()}
+	_, clockPkg, _ := check(t, fset, imp, "github.com/tilinna/clock", clockSrc)
+	imp.pkgs["github.com/tilinna/clock"] = clockPkg
+	file, _, info := check(t, fset, imp, "p", src)
+
+	r := &rewriter{fset: fset, info: info, ctx: "ctx"}
+	if !r.rewrite(file) {
+		t.Fatal("want file rewritten")
+	}
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, file); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != want {
+		t.Fatalf("want:\n%s\ngot:\n%s", want, got)
+	}
+	if len(r.problems) != 2 {
+		t.Fatalf("want 2 problems, got: %q", r.problems)
+	}
+	if got, want := r.problems[1], "p.go:38:8: cannot rewrite time.NewTimer: the result is used as a *time.Timer"; got != want {
+		t.Fatalf("want problem: %q, got: %q", want, got)
+	}
+}
@@ -0,0 +1,20 @@
+// Command clockvet reports the time and context calls that bypass the Clock
+// in packages which import github.com/tilinna/clock.
+//
+// Run it directly, optionally with -fix to apply the suggested fixes:
+//
+//	clockvet ./...
+//
+// or as a vet tool:
+//
+//	go vet -vettool=$(which clockvet) ./...
+package main
+
+import (
+	"github.com/tilinna/clock/analyzer"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(analyzer.Analyzer)
+}
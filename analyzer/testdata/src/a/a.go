@@ -0,0 +1,41 @@
+package a
+
+import (
+	"context"
+	"time"
+
+	"github.com/tilinna/clock"
+)
+
+func withCtx(ctx context.Context) {
+	_ = time.Now()                           // want `time.Now bypasses the Clock, use clock.Now`
+	time.Sleep(time.Second)                  // want `time.Sleep bypasses the Clock, use clock.Sleep`
+	_ = time.Since(clock.Now(ctx))           // want `time.Since bypasses the Clock, use clock.Since`
+	<-time.After(time.Second)                // want `time.After bypasses the Clock, use clock.After`
+	_, cancel := context.WithTimeout(ctx, 0) // want `context.WithTimeout bypasses the Clock, use clock.TimeoutContext`
+	defer cancel()
+	_ = time.Duration(0).Seconds()
+}
+
+func withoutCtx() {
+	_ = time.Now()                                                       // want `time.Now bypasses the Clock, use clock.Now`
+	_, cancel := context.WithDeadline(context.Background(), time.Time{}) // want `context.WithDeadline bypasses the Clock, use clock.DeadlineContext`
+	defer cancel()
+}
+
+type timers struct {
+	t *time.Timer
+}
+
+func results(ctx context.Context, x *timers) {
+	time.AfterFunc(time.Second, func() {}) // want `time.AfterFunc bypasses the Clock, use clock.AfterFunc`
+	t := time.NewTimer(time.Second)        // want `time.NewTimer bypasses the Clock, use clock.NewTimer`
+	defer t.Stop()
+	x.t = time.NewTimer(time.Second)  // want `time.NewTimer bypasses the Clock, use clock.NewTimer`
+	tk := time.NewTicker(time.Second) // want `time.NewTicker bypasses the Clock, use clock.NewTicker`
+	stopTicker(tk)
+}
+
+func stopTicker(t *time.Ticker) {
+	t.Stop()
+}
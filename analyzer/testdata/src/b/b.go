@@ -0,0 +1,8 @@
+package b
+
+import "time"
+
+// Packages not importing the clock package are not reported.
+func f() {
+	_ = time.Now()
+}
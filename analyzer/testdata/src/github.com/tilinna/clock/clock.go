@@ -0,0 +1,35 @@
+package clock
+
+import (
+	"context"
+	"time"
+)
+
+type Timer struct {
+	C <-chan time.Time
+}
+
+func (t *Timer) Reset(d time.Duration) bool { return false }
+func (t *Timer) Stop() bool                 { return false }
+
+type Ticker struct {
+	C <-chan time.Time
+}
+
+func (t *Ticker) Stop() {}
+
+func After(ctx context.Context, d time.Duration) <-chan time.Time     { return nil }
+func AfterFunc(ctx context.Context, d time.Duration, f func()) *Timer { return nil }
+func NewTicker(ctx context.Context, d time.Duration) *Ticker          { return nil }
+func NewTimer(ctx context.Context, d time.Duration) *Timer            { return nil }
+func Now(ctx context.Context) time.Time                               { return time.Time{} }
+func Since(ctx context.Context, t time.Time) time.Duration            { return 0 }
+func Sleep(ctx context.Context, d time.Duration)                      {}
+func Tick(ctx context.Context, d time.Duration) <-chan time.Time      { return nil }
+func Until(ctx context.Context, t time.Time) time.Duration            { return 0 }
+func DeadlineContext(ctx context.Context, d time.Time) (context.Context, context.CancelFunc) {
+	return ctx, nil
+}
+func TimeoutContext(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	return ctx, nil
+}
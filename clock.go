@@ -22,18 +22,48 @@ package clock
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
 // Clock represents an interface to the functions in the standard time and context packages.
 type Clock interface {
 	After(d time.Duration) <-chan time.Time
+	AfterAt(t time.Time) <-chan time.Time
 	AfterFunc(d time.Duration, f func()) *Timer
+	AfterFuncAt(t time.Time, f func()) *Timer
+
+	// NewAlignedTicker returns a new Ticker that ticks at the wall-clock
+	// multiples of period, shifted by offset.
+	NewAlignedTicker(period, offset time.Duration) *Ticker
+
+	// NewJitterTicker returns a new Ticker like NewTicker, except that each
+	// period is chosen randomly between period-jitter and period+jitter.
+	NewJitterTicker(period, jitter time.Duration, opts ...TickerOption) *Ticker
+
 	NewTicker(d time.Duration) *Ticker
+
+	// NewTickerWithOptions returns a new Ticker like NewTicker, configured
+	// by opts.
+	NewTickerWithOptions(d time.Duration, opts ...TickerOption) *Ticker
+
 	NewTimer(d time.Duration) *Timer
+	NewTimerAt(t time.Time) *Timer
 	Now() time.Time
+
+	// Instant returns the current reading of the monotonic clock.
+	Instant() Instant
+
 	Since(t time.Time) time.Duration
 	Sleep(d time.Duration)
+
+	// SleepContext pauses the current goroutine for at least the duration d,
+	// or until the context is done, in which case ctx.Err() is returned.
+	SleepContext(ctx context.Context, d time.Duration) error
+
+	// SleepUntil pauses the current goroutine at least until the time t.
+	SleepUntil(t time.Time)
+
 	Tick(d time.Duration) <-chan time.Time
 	Until(t time.Time) time.Duration
 
@@ -58,8 +88,32 @@ func (clock) After(d time.Duration) <-chan time.Time {
 	return time.After(d)
 }
 
+func (clock) AfterAt(t time.Time) <-chan time.Time {
+	return time.After(time.Until(t))
+}
+
 func (clock) AfterFunc(d time.Duration, f func()) *Timer {
-	return &Timer{timer: time.AfterFunc(d, f)}
+	return &Timer{
+		timer: time.AfterFunc(d, f),
+		state: newTimerState(d),
+	}
+}
+
+func (c clock) AfterFuncAt(t time.Time, f func()) *Timer {
+	return c.AfterFunc(time.Until(t), f)
+}
+
+func (clock) NewAlignedTicker(period, offset time.Duration) *Ticker {
+	if period <= 0 {
+		panic(errors.New("non-positive interval for NewAlignedTicker"))
+	}
+	return newRealtimeTicker(tickerOptions{}, true, func(now time.Time) time.Time {
+		return nextAligned(now, period, offset)
+	})
+}
+
+func (clock) NewJitterTicker(period, jitter time.Duration, opts ...TickerOption) *Ticker {
+	return newIntervalTicker(jitterInterval(realtimeRand, period, jitter), newTickerOptions(opts))
 }
 
 func (clock) NewTicker(d time.Duration) *Ticker {
@@ -67,7 +121,18 @@ func (clock) NewTicker(d time.Duration) *Ticker {
 	return &Ticker{
 		C:      t.C,
 		ticker: t,
+		state:  newTickerState(d),
+	}
+}
+
+func (c clock) NewTickerWithOptions(d time.Duration, opts ...TickerOption) *Ticker {
+	if len(opts) == 0 {
+		return c.NewTicker(d)
+	}
+	if d <= 0 {
+		panic(errors.New("non-positive interval for NewTickerWithOptions"))
 	}
+	return newIntervalTicker(fixedInterval(d), newTickerOptions(opts))
 }
 
 func (clock) NewTimer(d time.Duration) *Timer {
@@ -75,13 +140,22 @@ func (clock) NewTimer(d time.Duration) *Timer {
 	return &Timer{
 		C:     t.C,
 		timer: t,
+		state: newTimerState(d),
 	}
 }
 
+func (c clock) NewTimerAt(t time.Time) *Timer {
+	return c.NewTimer(time.Until(t))
+}
+
 func (clock) Now() time.Time {
 	return time.Now()
 }
 
+func (clock) Instant() Instant {
+	return Instant{time.Since(realtimeEpoch)}
+}
+
 func (clock) Since(t time.Time) time.Duration {
 	return time.Since(t)
 }
@@ -90,6 +164,24 @@ func (clock) Sleep(d time.Duration) {
 	time.Sleep(d)
 }
 
+func (clock) SleepContext(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (clock) SleepUntil(t time.Time) {
+	time.Sleep(time.Until(t))
+}
+
 func (clock) Tick(d time.Duration) <-chan time.Time {
 	// Using time.Tick would trigger a vet tool warning.
 	if d <= 0 {
@@ -102,10 +194,18 @@ func (clock) Until(t time.Time) time.Duration {
 	return time.Until(t)
 }
 
-func (clock) DeadlineContext(parent context.Context, d time.Time) (context.Context, context.CancelFunc) {
+func (c clock) DeadlineContext(parent context.Context, d time.Time) (context.Context, context.CancelFunc) {
+	if owner, ok := DeadlineClock(parent); ok && owner != Clock(c) {
+		// Hide the foreign deadline from context.WithDeadline, which would
+		// compare it with d as is.
+		if pd, _, ok := parentDeadline(c, parent, TranslateDeadlines); ok && pd.Before(d) {
+			d = pd
+		}
+		parent = hiddenDeadlineCtx{parent}
+	}
 	return context.WithDeadline(parent, d)
 }
 
-func (clock) TimeoutContext(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
-	return context.WithTimeout(parent, timeout)
+func (c clock) TimeoutContext(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	return c.DeadlineContext(parent, time.Now().Add(timeout))
 }
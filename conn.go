@@ -0,0 +1,126 @@
+//go:build go1.15
+// +build go1.15
+
+package clock
+
+import (
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// WrapConn returns a net.Conn whose read and write deadlines follow the
+// Clock c instead of the real time. On expiry, blocked and future reads or
+// writes fail with os.ErrDeadlineExceeded, like with the deadlines of the
+// standard library.
+//
+// The deadlines are implemented with the timers of c and by setting the
+// deadlines of the underlying conn to the past, so conn must support
+// deadlines, as do the connections of the net package and net.Pipe.
+func WrapConn(c Clock, conn net.Conn) net.Conn {
+	return &clockConn{
+		Conn:  conn,
+		read:  connDeadline{clock: c, set: conn.SetReadDeadline},
+		write: connDeadline{clock: c, set: conn.SetWriteDeadline},
+	}
+}
+
+type clockConn struct {
+	net.Conn
+	read  connDeadline
+	write connDeadline
+}
+
+func (c *clockConn) Read(b []byte) (int, error) {
+	if c.read.expired() {
+		return 0, os.ErrDeadlineExceeded
+	}
+	n, err := c.Conn.Read(b)
+	return n, c.read.err(err)
+}
+
+func (c *clockConn) Write(b []byte) (int, error) {
+	if c.write.expired() {
+		return 0, os.ErrDeadlineExceeded
+	}
+	n, err := c.Conn.Write(b)
+	return n, c.write.err(err)
+}
+
+func (c *clockConn) SetDeadline(t time.Time) error {
+	if err := c.read.reset(t); err != nil {
+		return err
+	}
+	return c.write.reset(t)
+}
+
+func (c *clockConn) SetReadDeadline(t time.Time) error {
+	return c.read.reset(t)
+}
+
+func (c *clockConn) SetWriteDeadline(t time.Time) error {
+	return c.write.reset(t)
+}
+
+// pastDeadline is used to interrupt the blocked operations of a conn.
+var pastDeadline = time.Unix(1, 0)
+
+// connDeadline implements a read or write deadline with a Clock timer.
+type connDeadline struct {
+	clock Clock
+	set   func(time.Time) error
+
+	mu     sync.Mutex
+	timer  *Timer
+	gen    int // invalidates the previous timers
+	isPast bool
+}
+
+// reset changes the deadline to t. A zero t means no deadline.
+func (d *connDeadline) reset(t time.Time) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.gen++
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+	if t.IsZero() {
+		d.isPast = false
+		return d.set(time.Time{})
+	}
+	if d.clock.Until(t) <= 0 {
+		d.isPast = true
+		return d.set(pastDeadline)
+	}
+	d.isPast = false
+	if err := d.set(time.Time{}); err != nil {
+		return err
+	}
+	gen := d.gen
+	d.timer = d.clock.AfterFuncAt(t, func() {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		if d.gen != gen {
+			return
+		}
+		d.isPast = true
+		d.set(pastDeadline)
+	})
+	return nil
+}
+
+func (d *connDeadline) expired() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.isPast
+}
+
+// err translates the timeout errors caused by an expired deadline.
+func (d *connDeadline) err(err error) error {
+	if ne, ok := err.(net.Error); ok && ne.Timeout() && d.expired() {
+		return os.ErrDeadlineExceeded
+	}
+	return err
+}
@@ -0,0 +1,67 @@
+//go:build go1.15
+// +build go1.15
+
+package clock_test
+
+import (
+	"errors"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/tilinna/clock"
+)
+
+func TestWrapConn(t *testing.T) {
+	m := clock.NewMock(testTime)
+	c1, c2 := net.Pipe()
+	defer c2.Close()
+	conn := clock.WrapConn(m, c1)
+	defer conn.Close()
+
+	if err := conn.SetReadDeadline(m.Now().Add(5 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	errc := make(chan error, 1)
+	go func() {
+		_, err := conn.Read(make([]byte, 1))
+		errc <- err
+	}()
+	m.Add(4 * time.Second)
+	select {
+	case err := <-errc:
+		t.Fatalf("want Read blocked before the deadline, got: %v", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+	m.Add(time.Second)
+	if err := <-errc; !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Fatalf("want Read: %v, got: %v", os.ErrDeadlineExceeded, err)
+	}
+	if _, err := conn.Read(make([]byte, 1)); !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Fatalf("want Read after deadline: %v, got: %v", os.ErrDeadlineExceeded, err)
+	}
+
+	// Extending the deadline makes the conn usable again.
+	if err := conn.SetDeadline(m.Now().Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	go c2.Write([]byte("x"))
+	if _, err := conn.Read(make([]byte, 1)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := conn.SetWriteDeadline(m.Now().Add(-time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Write([]byte("x")); !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Fatalf("want Write: %v, got: %v", os.ErrDeadlineExceeded, err)
+	}
+	if got, want := m.Len(), 1; got != want {
+		t.Fatalf("want m.Len(): %d, got: %d", want, got)
+	}
+	conn.SetDeadline(time.Time{})
+	if got, want := m.Len(), 0; got != want {
+		t.Fatalf("want m.Len(): %d, got: %d", want, got)
+	}
+}
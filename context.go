@@ -20,31 +20,109 @@ func FromContext(ctx context.Context) Clock {
 	return Realtime()
 }
 
+type deadlineKey struct{}
+
+// deadlineOwner is the value of deadlineKey in the contexts created by
+// Clocks other than Realtime.
+type deadlineOwner struct {
+	clock    Clock
+	deadline time.Time
+}
+
+// DeadlineClock returns the Clock which owns the deadline of the context,
+// or false if the context has no deadline. Deadlines not set with the
+// DeadlineContext or TimeoutContext of a Mock, Pausable or other non-real
+// Clock, such as those from context.WithTimeout, are owned by Realtime().
+func DeadlineClock(ctx context.Context) (Clock, bool) {
+	d, ok := ctx.Deadline()
+	if !ok {
+		return nil, false
+	}
+	if o, ok := ctx.Value(deadlineKey{}).(deadlineOwner); ok && o.deadline.Equal(d) {
+		return o.clock, true
+	}
+	return Realtime(), true
+}
+
+// parentDeadline returns the deadline of parent in the time of c, and whether
+// it is owned by c. A deadline owned by another Clock is translated by the
+// difference between the current times of the Clocks, or ignored according
+// to policy.
+func parentDeadline(c Clock, parent context.Context, policy DeadlinePolicy) (d time.Time, own, ok bool) {
+	owner, ok := DeadlineClock(parent)
+	if !ok {
+		return time.Time{}, false, false
+	}
+	d, _ = parent.Deadline()
+	if owner == c {
+		return d, true, true
+	}
+	if policy == IgnoreDeadlines {
+		return time.Time{}, false, false
+	}
+	return d.Add(c.Now().Sub(owner.Now())), false, true
+}
+
 // After is a convenience wrapper for FromContext(ctx).After.
 func After(ctx context.Context, d time.Duration) <-chan time.Time {
 	return FromContext(ctx).After(d)
 }
 
+// AfterAt is a convenience wrapper for FromContext(ctx).AfterAt.
+func AfterAt(ctx context.Context, t time.Time) <-chan time.Time {
+	return FromContext(ctx).AfterAt(t)
+}
+
 // AfterFunc is a convenience wrapper for FromContext(ctx).AfterFunc.
 func AfterFunc(ctx context.Context, d time.Duration, f func()) *Timer {
 	return FromContext(ctx).AfterFunc(d, f)
 }
 
+// AfterFuncAt is a convenience wrapper for FromContext(ctx).AfterFuncAt.
+func AfterFuncAt(ctx context.Context, t time.Time, f func()) *Timer {
+	return FromContext(ctx).AfterFuncAt(t, f)
+}
+
+// NewJitterTicker is a convenience wrapper for FromContext(ctx).NewJitterTicker.
+func NewJitterTicker(ctx context.Context, period, jitter time.Duration, opts ...TickerOption) *Ticker {
+	return FromContext(ctx).NewJitterTicker(period, jitter, opts...)
+}
+
 // NewTicker is a convenience wrapper for FromContext(ctx).NewTicker.
 func NewTicker(ctx context.Context, d time.Duration) *Ticker {
 	return FromContext(ctx).NewTicker(d)
 }
 
+// NewTickerWithOptions is a convenience wrapper for FromContext(ctx).NewTickerWithOptions.
+func NewTickerWithOptions(ctx context.Context, d time.Duration, opts ...TickerOption) *Ticker {
+	return FromContext(ctx).NewTickerWithOptions(d, opts...)
+}
+
+// NewAlignedTicker is a convenience wrapper for FromContext(ctx).NewAlignedTicker.
+func NewAlignedTicker(ctx context.Context, period, offset time.Duration) *Ticker {
+	return FromContext(ctx).NewAlignedTicker(period, offset)
+}
+
 // NewTimer is a convenience wrapper for FromContext(ctx).NewTimer.
 func NewTimer(ctx context.Context, d time.Duration) *Timer {
 	return FromContext(ctx).NewTimer(d)
 }
 
+// NewTimerAt is a convenience wrapper for FromContext(ctx).NewTimerAt.
+func NewTimerAt(ctx context.Context, t time.Time) *Timer {
+	return FromContext(ctx).NewTimerAt(t)
+}
+
 // Now is a convenience wrapper for FromContext(ctx).Now.
 func Now(ctx context.Context) time.Time {
 	return FromContext(ctx).Now()
 }
 
+// NowInstant is a convenience wrapper for FromContext(ctx).Instant.
+func NowInstant(ctx context.Context) Instant {
+	return FromContext(ctx).Instant()
+}
+
 // Since is a convenience wrapper for FromContext(ctx).Since.
 func Since(ctx context.Context, t time.Time) time.Duration {
 	return FromContext(ctx).Since(t)
@@ -55,6 +133,16 @@ func Sleep(ctx context.Context, d time.Duration) {
 	FromContext(ctx).Sleep(d)
 }
 
+// SleepContext is a convenience wrapper for FromContext(ctx).SleepContext.
+func SleepContext(ctx context.Context, d time.Duration) error {
+	return FromContext(ctx).SleepContext(ctx, d)
+}
+
+// SleepUntil is a convenience wrapper for FromContext(ctx).SleepUntil.
+func SleepUntil(ctx context.Context, t time.Time) {
+	FromContext(ctx).SleepUntil(t)
+}
+
 // Tick is a convenience wrapper for FromContext(ctx).Tick.
 func Tick(ctx context.Context, d time.Duration) <-chan time.Time {
 	return FromContext(ctx).Tick(d)
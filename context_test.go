@@ -105,6 +105,37 @@ func Test_Context(t *testing.T) {
 	}
 }
 
+func TestSleepContext(t *testing.T) {
+	m := clock.NewMock(testTime)
+	ctx, cancel := context.WithCancel(clock.Context(context.Background(), m))
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- clock.SleepContext(ctx, time.Hour)
+	}()
+	m.BlockUntil(1)
+	cancel()
+	if got, want := <-errc, context.Canceled; got != want {
+		t.Fatalf("want SleepContext: %q, got: %q", want, got)
+	}
+	if got, want := m.Len(), 0; got != want {
+		t.Fatalf("want m.Len(): %d, got: %d", want, got)
+	}
+
+	go func() {
+		errc <- m.SleepContext(context.Background(), time.Hour)
+	}()
+	m.BlockUntil(1)
+	m.Add(time.Hour)
+	if err := <-errc; err != nil {
+		t.Fatalf("want SleepContext: <nil>, got: %q", err)
+	}
+
+	if got, want := clock.Realtime().SleepContext(ctx, time.Hour), context.Canceled; got != want {
+		t.Fatalf("want Realtime().SleepContext: %q, got: %q", want, got)
+	}
+}
+
 func ExampleMock_DeadlineContext() {
 	start := time.Date(2018, 1, 1, 10, 0, 0, 0, time.UTC)
 	mock := clock.NewMock(start)
@@ -123,3 +154,95 @@ func ExampleMock_DeadlineContext() {
 	// now: 2018-01-01 11:00:00 +0000 UTC
 	// err: context deadline exceeded
 }
+
+func TestDeadlineClock(t *testing.T) {
+	m := clock.NewMock(testTime)
+	if _, ok := clock.DeadlineClock(context.Background()); ok {
+		t.Fatal("want no deadline")
+	}
+
+	rctx, rcancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer rcancel()
+	if c, _ := clock.DeadlineClock(rctx); c != clock.Realtime() {
+		t.Fatalf("want Realtime owner, got: %v", c)
+	}
+
+	ctx, cancel := m.TimeoutContext(context.Background(), time.Minute)
+	defer cancel()
+	if c, _ := clock.DeadlineClock(ctx); c != m {
+		t.Fatalf("want Mock owner, got: %v", c)
+	}
+
+	// The real deadline of 10s is translated to about testTime+10s, and
+	// enforced by the Mock.
+	tm := clock.NewMock(testTime)
+	ctx, cancel = tm.TimeoutContext(rctx, time.Minute)
+	defer cancel()
+	if c, _ := clock.DeadlineClock(ctx); c != tm {
+		t.Fatalf("want Mock owner, got: %v", c)
+	}
+	if d, _ := ctx.Deadline(); d.After(testTime.Add(10*time.Second)) || d.Before(testTime.Add(9*time.Second)) {
+		t.Fatalf("want Deadline about %v, got: %v", testTime.Add(10*time.Second), d)
+	}
+	if got, want := tm.Len(), 1; got != want {
+		t.Fatalf("want m.Len(): %d, got: %d", want, got)
+	}
+	tm.Add(10 * time.Second)
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("want Done after the translated deadline")
+	}
+	if got, want := ctx.Err(), context.DeadlineExceeded; got != want {
+		t.Fatalf("want Err: %v, got: %v", want, got)
+	}
+
+	im := clock.NewMock(testTime, clock.WithDeadlinePolicy(clock.IgnoreDeadlines))
+	ctx, cancel = im.TimeoutContext(rctx, time.Minute)
+	defer cancel()
+	if c, _ := clock.DeadlineClock(ctx); c != im {
+		t.Fatalf("want Mock owner, got: %v", c)
+	}
+	if d, _ := ctx.Deadline(); !d.Equal(testTime.Add(time.Minute)) {
+		t.Fatalf("want Deadline: %v, got: %v", testTime.Add(time.Minute), d)
+	}
+
+	mctx, mcancel := m.TimeoutContext(context.Background(), time.Hour)
+	defer mcancel()
+	ctx, cancel = clock.Realtime().TimeoutContext(mctx, 2*time.Hour)
+	defer cancel()
+	if c, _ := clock.DeadlineClock(ctx); c != clock.Realtime() {
+		t.Fatalf("want Realtime owner, got: %v", c)
+	}
+	if d, _ := ctx.Deadline(); time.Until(d) > time.Hour {
+		t.Fatalf("want Deadline within an hour, got: %v", time.Until(d))
+	}
+	m.Add(time.Hour)
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("want Done after the Mock deadline")
+	}
+
+	oc := clock.Offset(m, time.Hour)
+	ctx, cancel = oc.TimeoutContext(context.Background(), time.Minute)
+	defer cancel()
+	if c, _ := clock.DeadlineClock(ctx); c != oc {
+		t.Fatalf("want Offset owner, got: %v", c)
+	}
+	// The Offset deadline is translated to m.Now()+1m, which is earlier.
+	ctx, cancel = m.TimeoutContext(ctx, 2*time.Minute)
+	defer cancel()
+	if c, _ := clock.DeadlineClock(ctx); c != m {
+		t.Fatalf("want Mock owner, got: %v", c)
+	}
+	if d, _ := ctx.Deadline(); !d.Equal(m.Now().Add(time.Minute)) {
+		t.Fatalf("want Deadline: %v, got: %v", m.Now().Add(time.Minute), d)
+	}
+	m.Add(time.Minute)
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("want Done after the translated deadline")
+	}
+}
@@ -0,0 +1,128 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// realtimeState tracks the deadline of a realtime Timer or Ticker.
+type realtimeState struct {
+	mu       sync.Mutex
+	deadline time.Time // zero if not known
+	period   time.Duration
+	expires  bool
+	active   bool
+
+	paused          bool
+	pausedRemaining time.Duration
+}
+
+func newTimerState(d time.Duration) *realtimeState {
+	return &realtimeState{
+		deadline: time.Now().Add(d),
+		expires:  true,
+		active:   true,
+	}
+}
+
+func newTickerState(d time.Duration) *realtimeState {
+	return &realtimeState{
+		deadline: time.Now().Add(d),
+		period:   d,
+		active:   true,
+	}
+}
+
+// get returns the current deadline and whether the state is still active.
+func (s *realtimeState) get() (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.active {
+		return time.Time{}, false
+	}
+	if s.paused {
+		return time.Time{}, true
+	}
+	now := time.Now()
+	if s.period > 0 && !now.Before(s.deadline) {
+		s.deadline = s.deadline.Add((now.Sub(s.deadline)/s.period + 1) * s.period)
+	}
+	if s.expires && !now.Before(s.deadline) {
+		s.active = false
+		return time.Time{}, false
+	}
+	return s.deadline, true
+}
+
+func (s *realtimeState) set(deadline time.Time, active bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deadline = deadline
+	s.active = active
+}
+
+// update changes the deadline unless the state is already inactive.
+func (s *realtimeState) update(deadline time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.active {
+		s.deadline = deadline
+	}
+}
+
+func (s *realtimeState) deadlineOf() (time.Time, bool) {
+	d, active := s.get()
+	return d, active && !d.IsZero()
+}
+
+func (s *realtimeState) remaining() time.Duration {
+	s.mu.Lock()
+	paused, remaining := s.paused, s.pausedRemaining
+	s.mu.Unlock()
+	if paused {
+		return remaining
+	}
+	d, ok := s.deadlineOf()
+	if !ok {
+		return 0
+	}
+	if r := time.Until(d); r > 0 {
+		return r
+	}
+	return 0
+}
+
+// mockDeadline returns the deadline of a Mock timer.
+// The Mock must be locked.
+func (t *mockTimer) mockDeadline() (time.Time, bool) {
+	if t.stopped() {
+		return time.Time{}, false
+	}
+	return t.mock.wallAt(t.deadline), true
+}
+
+// mockRemaining returns the duration until the deadline of a Mock timer.
+// The Mock must be locked.
+func (t *mockTimer) mockRemaining() time.Duration {
+	if t.paused {
+		return t.pausedRemaining
+	}
+	if t.stopped() {
+		return 0
+	}
+	return t.deadline.Sub(t.mock.now)
+}
+
+// mockActive reports whether a Mock timer is started, paused or waiting to
+// be rearmed. The Mock must be locked.
+func (t *mockTimer) mockActive() bool {
+	if !t.stopped() || t.paused {
+		return true
+	}
+	for _, p := range t.mock.pending {
+		if p == t {
+			return true
+		}
+	}
+	return false
+}
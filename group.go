@@ -0,0 +1,166 @@
+package clock
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"time"
+)
+
+// MockGroup advances several Mocks in lockstep, such as the clocks of the
+// nodes of a simulated cluster. Each member Mock has its own offset and
+// drift rate relative to the time of the group.
+//
+// Add, AddNext and Set of the group fire the timers of all members in the
+// order of their deadlines in the group time, and the other members are
+// advanced to the same instant before each timer fires. Unlike Mock.Add,
+// Tickers tick at each of their deadlines.
+//
+// The members should not be advanced directly.
+type MockGroup struct {
+	mu      sync.Mutex
+	now     time.Time
+	members []*groupMember
+}
+
+type groupMember struct {
+	mock  *Mock
+	start time.Time // the group time when the member was created
+	base  time.Time // the member time at start
+	rate  float64
+}
+
+// NewMockGroup returns a new empty MockGroup with current time set to now.
+func NewMockGroup(now time.Time) *MockGroup {
+	return &MockGroup{now: now}
+}
+
+// NewMock returns a new Mock in the group, whose current time is ahead of
+// the group by offset, and which runs rate times as fast as the group.
+// For example, a rate of 1.0001 gains 100µs per second.
+func (g *MockGroup) NewMock(offset time.Duration, rate float64, opts ...MockOption) *Mock {
+	if rate <= 0 {
+		panic(errors.New("non-positive rate for MockGroup.NewMock"))
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	m := NewMock(g.now.Add(offset), opts...)
+	g.members = append(g.members, &groupMember{
+		mock:  m,
+		start: g.now,
+		base:  m.now,
+		rate:  rate,
+	})
+	return m
+}
+
+// at returns the member time at the group time t.
+func (gm *groupMember) at(t time.Time) time.Time {
+	return gm.base.Add(time.Duration(float64(t.Sub(gm.start)) * gm.rate))
+}
+
+// groupTime returns the earliest group time at which the member time is at
+// least t.
+func (gm *groupMember) groupTime(t time.Time) time.Time {
+	gt := gm.start.Add(time.Duration(math.Ceil(float64(t.Sub(gm.base)) / gm.rate)))
+	for gm.at(gt).Before(t) {
+		gt = gt.Add(1)
+	}
+	return gt
+}
+
+// Now returns the current time of the group.
+func (g *MockGroup) Now() time.Time {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.now
+}
+
+// Add advances the current time of the group by duration d and fires all
+// expired timers of the members.
+//
+// Returns the new current time.
+func (g *MockGroup) Add(d time.Duration) time.Time {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.advance(g.now.Add(d))
+	return g.now
+}
+
+// AddNext advances the current time of the group to the earliest timer
+// deadline of the members and fires all expired timers.
+//
+// Returns the new current time and the advanced duration.
+func (g *MockGroup) AddNext() (time.Time, time.Duration) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	next, ok := g.next()
+	if !ok {
+		return g.now, 0
+	}
+	cur := g.now
+	g.advance(next)
+	return g.now, g.now.Sub(cur)
+}
+
+// Set advances the current time of the group to t and fires all expired
+// timers of the members.
+//
+// Returns the advanced duration.
+func (g *MockGroup) Set(t time.Time) time.Duration {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	cur := g.now
+	g.advance(t)
+	return g.now.Sub(cur)
+}
+
+// next returns the earliest timer deadline of the members in the group time.
+func (g *MockGroup) next() (time.Time, bool) {
+	var next time.Time
+	found := false
+	for _, gm := range g.members {
+		m := gm.mock
+		m.lock()
+		m.poll()
+		t := m.next()
+		var deadline time.Time
+		if t != nil {
+			deadline = t.deadline
+		}
+		m.Unlock()
+		if t == nil {
+			continue
+		}
+		if gt := gm.groupTime(deadline); !found || gt.Before(next) {
+			next, found = gt, true
+		}
+	}
+	return next, found
+}
+
+// advance moves the group to the time target, stopping at each timer
+// deadline on the way.
+func (g *MockGroup) advance(target time.Time) {
+	for {
+		next, ok := g.next()
+		if !ok || !next.Before(target) {
+			break
+		}
+		if next.Before(g.now) {
+			next = g.now
+		}
+		g.setAll(next)
+	}
+	g.setAll(target)
+}
+
+func (g *MockGroup) setAll(t time.Time) {
+	g.now = t
+	for _, gm := range g.members {
+		m := gm.mock
+		m.lock()
+		m.set(gm.at(t))
+		m.Unlock()
+	}
+}
@@ -0,0 +1,72 @@
+package clock_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tilinna/clock"
+)
+
+func TestMockGroup(t *testing.T) {
+	g := clock.NewMockGroup(testTime)
+	a := g.NewMock(0, 1)
+	b := g.NewMock(time.Hour, 2)
+	if got, want := b.Now(), testTime.Add(time.Hour); !got.Equal(want) {
+		t.Fatalf("want b.Now(): %v, got: %v", want, got)
+	}
+
+	ta := a.NewTimer(3 * time.Second)
+	tb := b.NewTimer(4 * time.Second) // 2s in the group time
+	fired := func(tm *clock.Timer) bool {
+		select {
+		case <-tm.C:
+			return true
+		default:
+			return false
+		}
+	}
+
+	now, d := g.AddNext()
+	if !now.Equal(testTime.Add(2*time.Second)) || d != 2*time.Second {
+		t.Fatalf("want AddNext: %v %v, got: %v %v", testTime.Add(2*time.Second), 2*time.Second, now, d)
+	}
+	if fired(ta) || !fired(tb) {
+		t.Fatal("want only b's timer fired")
+	}
+	if got, want := a.Now(), testTime.Add(2*time.Second); !got.Equal(want) {
+		t.Fatalf("want a.Now(): %v, got: %v", want, got)
+	}
+
+	g.AddNext()
+	if !fired(ta) {
+		t.Fatal("want a's timer fired")
+	}
+	if got, want := b.Now(), testTime.Add(time.Hour+6*time.Second); !got.Equal(want) {
+		t.Fatalf("want b.Now(): %v, got: %v", want, got)
+	}
+
+	// b's ticker ticks every 5s in the group time.
+	ticker := b.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+	for i := 0; i < 3; i++ {
+		g.AddNext()
+		select {
+		case tick := <-ticker.C:
+			if want := testTime.Add(time.Hour + time.Duration(16+10*i)*time.Second); !tick.Equal(want) {
+				t.Fatalf("want tick %d: %v, got: %v", i, want, tick)
+			}
+		default:
+			t.Fatalf("want tick %d, got none", i)
+		}
+	}
+
+	if got, want := g.Set(testTime.Add(time.Minute)), 42*time.Second; got != want {
+		t.Fatalf("want Set: %v, got: %v", want, got)
+	}
+	if got, want := b.Now(), testTime.Add(time.Hour+2*time.Minute); !got.Equal(want) {
+		t.Fatalf("want b.Now(): %v, got: %v", want, got)
+	}
+	if got, want := g.Add(time.Second), testTime.Add(time.Minute+time.Second); !got.Equal(want) {
+		t.Fatalf("want Add: %v, got: %v", want, got)
+	}
+}
@@ -8,8 +8,20 @@ import (
 type mockTimer struct {
 	deadline  time.Time
 	fire      func() time.Duration
+	rearm     func() bool
 	mock      *Mock
 	heapIndex int
+
+	paused          bool
+	pausedRemaining time.Duration
+
+	// Set by NewAlignedTicker, returns the first boundary after now.
+	align func(now time.Time) time.Time
+
+	// Set by WithTimeline.
+	id   int
+	kind string
+	site string
 }
 
 const removed = -1
@@ -75,6 +87,15 @@ func (h *timerHeap) reset(t *mockTimer) {
 	}
 }
 
+// update calls f for all timers, which may change their deadlines, and
+// restores the heap order.
+func (h *timerHeap) update(f func(t *mockTimer)) {
+	for _, t := range *h {
+		f(t)
+	}
+	heap.Init(h)
+}
+
 func (h timerHeap) next() *mockTimer {
 	if len(h) == 0 {
 		return nil
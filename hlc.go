@@ -0,0 +1,157 @@
+package clock
+
+import (
+	"encoding/binary"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	hlcLogicalBits = 16
+	hlcLogicalMask = 1<<hlcLogicalBits - 1
+	hlcMaxWall     = 1<<(64-hlcLogicalBits) - 1
+)
+
+// HLCTimestamp is a hybrid logical clock timestamp.
+//
+// Timestamps are ordered first by Wall and then by Logical.
+type HLCTimestamp struct {
+	// Wall is the physical component in milliseconds since the Unix epoch.
+	// Only the low 48 bits are retained by the encodings.
+	Wall int64
+	// Logical is the logical counter which orders the timestamps with the
+	// same Wall.
+	Logical uint16
+}
+
+// HLCTimestampFromUint64 decodes a timestamp encoded with Uint64.
+func HLCTimestampFromUint64(u uint64) HLCTimestamp {
+	return HLCTimestamp{
+		Wall:    int64(u >> hlcLogicalBits),
+		Logical: uint16(u & hlcLogicalMask),
+	}
+}
+
+// Uint64 encodes the timestamp to an uint64, whose natural order is the same
+// as the order of the timestamps.
+func (ts HLCTimestamp) Uint64() uint64 {
+	return uint64(ts.Wall)<<hlcLogicalBits | uint64(ts.Logical)
+}
+
+// MarshalBinary encodes the timestamp to 8 bytes in big-endian order,
+// which sort in the same order as the timestamps.
+func (ts HLCTimestamp) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, ts.Uint64())
+	return b, nil
+}
+
+// UnmarshalBinary decodes a timestamp encoded with MarshalBinary.
+func (ts *HLCTimestamp) UnmarshalBinary(b []byte) error {
+	if len(b) != 8 {
+		return errors.New("clock: invalid HLC timestamp length")
+	}
+	*ts = HLCTimestampFromUint64(binary.BigEndian.Uint64(b))
+	return nil
+}
+
+// Time returns the physical component as a time.Time.
+func (ts HLCTimestamp) Time() time.Time {
+	return time.Unix(0, ts.Wall*int64(time.Millisecond))
+}
+
+// Compare returns -1, 0 or +1 depending on whether ts is before, equal to or
+// after u.
+func (ts HLCTimestamp) Compare(u HLCTimestamp) int {
+	switch {
+	case ts.Wall < u.Wall:
+		return -1
+	case ts.Wall > u.Wall:
+		return 1
+	case ts.Logical < u.Logical:
+		return -1
+	case ts.Logical > u.Logical:
+		return 1
+	}
+	return 0
+}
+
+// Before reports whether ts is before u.
+func (ts HLCTimestamp) Before(u HLCTimestamp) bool {
+	return ts.Compare(u) < 0
+}
+
+// String returns the timestamp formatted as "wall.logical".
+func (ts HLCTimestamp) String() string {
+	return strconv.FormatInt(ts.Wall, 10) + "." + strconv.FormatUint(uint64(ts.Logical), 10)
+}
+
+// HLC implements a hybrid logical clock, which produces causally ordered
+// timestamps close to the physical time of a Clock.
+//
+// Use Now for local and send events, and Update for receive events.
+type HLC struct {
+	clock Clock
+	mu    sync.Mutex
+	last  HLCTimestamp
+}
+
+// NewHLC returns a new HLC which reads the physical time from c.
+func NewHLC(c Clock) *HLC {
+	return &HLC{clock: c}
+}
+
+func (h *HLC) physical() int64 {
+	return h.clock.Now().UnixNano() / int64(time.Millisecond) & hlcMaxWall
+}
+
+// Now returns a new timestamp, which is after all the timestamps
+// previously returned by h.
+func (h *HLC) Now() HLCTimestamp {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if pt := h.physical(); pt > h.last.Wall {
+		h.last = HLCTimestamp{Wall: pt}
+	} else {
+		h.tick(h.last.Logical)
+	}
+	return h.last
+}
+
+// Update merges a timestamp received from a remote HLC and returns a new
+// timestamp, which is after both remote and all the timestamps previously
+// returned by h.
+func (h *HLC) Update(remote HLCTimestamp) HLCTimestamp {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	pt := h.physical()
+	switch {
+	case pt > h.last.Wall && pt > remote.Wall:
+		h.last = HLCTimestamp{Wall: pt}
+	case h.last.Wall == remote.Wall:
+		logical := h.last.Logical
+		if remote.Logical > logical {
+			logical = remote.Logical
+		}
+		h.tick(logical)
+	case h.last.Wall > remote.Wall:
+		h.tick(h.last.Logical)
+	default:
+		h.last.Wall = remote.Wall
+		h.tick(remote.Logical)
+	}
+	return h.last
+}
+
+// tick sets the logical counter of h.last to logical+1. On overflow, the
+// physical component is advanced instead.
+func (h *HLC) tick(logical uint16) {
+	if logical == hlcLogicalMask {
+		h.last.Wall++
+		h.last.Logical = 0
+		return
+	}
+	h.last.Logical = logical + 1
+}
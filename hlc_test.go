@@ -0,0 +1,59 @@
+package clock_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tilinna/clock"
+)
+
+func TestHLC(t *testing.T) {
+	ma := clock.NewMock(testTime)
+	mb := clock.NewMock(testTime.Add(-time.Second)) // b is lagging behind
+	a, b := clock.NewHLC(ma), clock.NewHLC(mb)
+
+	t1 := a.Now()
+	t2 := a.Now()
+	if !t1.Before(t2) || t2.Logical != 1 {
+		t.Fatalf("want increasing logical counter, got: %s, %s", t1, t2)
+	}
+	if !t1.Time().Equal(testTime) {
+		t.Fatalf("want t1.Time(): %s, got: %s", testTime, t1.Time())
+	}
+
+	// Receive event on the lagging node.
+	t3 := b.Update(t2)
+	if !t2.Before(t3) || t3.Wall != t2.Wall {
+		t.Fatalf("want causal order after receive, got: %s, %s", t2, t3)
+	}
+	t4 := b.Now()
+	if !t3.Before(t4) {
+		t.Fatalf("want %s before %s", t3, t4)
+	}
+
+	// Physical time catches up.
+	mb.Add(2 * time.Second)
+	t5 := b.Now()
+	if want := (clock.HLCTimestamp{Wall: t1.Wall + 1000}); t5 != want {
+		t.Fatalf("want %s, got: %s", want, t5)
+	}
+	t6 := a.Update(t5)
+	if !t5.Before(t6) || t6.Logical != 1 {
+		t.Fatalf("want %s before %s", t5, t6)
+	}
+
+	b1, err := t6.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var t7 clock.HLCTimestamp
+	if err := t7.UnmarshalBinary(b1); err != nil {
+		t.Fatal(err)
+	}
+	if t7 != t6 || clock.HLCTimestampFromUint64(t6.Uint64()) != t6 {
+		t.Fatalf("want round trip of %s, got: %s", t6, t7)
+	}
+	if t1.Uint64() >= t2.Uint64() || t2.Uint64() >= t6.Uint64() {
+		t.Fatal("want encoded timestamps in order")
+	}
+}
@@ -0,0 +1,262 @@
+package clock
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// OffsetHeader is the request header read by OffsetMiddleware.
+const OffsetHeader = "X-Clock-Offset"
+
+// ErrIdleTimeout is returned by the requests of IdleTimeoutTransport, which
+// have made no progress for the idle timeout.
+var ErrIdleTimeout = errors.New("clock: idle timeout")
+
+// Transport returns an http.RoundTripper which limits the duration of each
+// request made with base, including reading the response body, to timeout.
+//
+// Unlike http.Client.Timeout, the timeout is applied with c.TimeoutContext,
+// so it can be controlled with a Mock. A nil base uses http.DefaultTransport.
+func Transport(c Clock, base http.RoundTripper, timeout time.Duration) http.RoundTripper {
+	return &timeoutTransport{
+		clock:   c,
+		base:    transportOrDefault(base),
+		timeout: timeout,
+	}
+}
+
+func transportOrDefault(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		return http.DefaultTransport
+	}
+	return base
+}
+
+type timeoutTransport struct {
+	clock   Clock
+	base    http.RoundTripper
+	timeout time.Duration
+}
+
+func (t *timeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, cancel := t.clock.TimeoutContext(req.Context(), t.timeout)
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelBody cancels the context of the request when the body is closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// IdleTimeoutTransport returns an http.RoundTripper which cancels the
+// requests made with base, that have made no progress for the duration
+// idle. The request is considered to make progress when the response
+// headers are received and whenever the response body is read.
+//
+// Idle requests fail with ErrIdleTimeout. The timeout is applied with the
+// timers of c, so it can be controlled with a Mock. A nil base uses
+// http.DefaultTransport.
+func IdleTimeoutTransport(c Clock, base http.RoundTripper, idle time.Duration) http.RoundTripper {
+	return &idleTransport{
+		clock: c,
+		base:  transportOrDefault(base),
+		idle:  idle,
+	}
+}
+
+type idleTransport struct {
+	clock Clock
+	base  http.RoundTripper
+	idle  time.Duration
+}
+
+func (t *idleTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithCancel(req.Context())
+	w := &idleWatch{cancel: cancel}
+	w.timer = t.clock.AfterFunc(t.idle, w.expire)
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		w.timer.Stop()
+		cancel()
+		if w.isExpired() {
+			return nil, ErrIdleTimeout
+		}
+		return nil, err
+	}
+	w.timer.Reset(t.idle)
+	resp.Body = &idleBody{ReadCloser: resp.Body, watch: w, idle: t.idle}
+	return resp, nil
+}
+
+// idleWatch cancels a request when its timer expires.
+type idleWatch struct {
+	timer   *Timer
+	cancel  context.CancelFunc
+	mu      sync.Mutex
+	expired bool
+}
+
+func (w *idleWatch) expire() {
+	w.mu.Lock()
+	w.expired = true
+	w.mu.Unlock()
+	w.cancel()
+}
+
+func (w *idleWatch) isExpired() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.expired
+}
+
+type idleBody struct {
+	io.ReadCloser
+	watch *idleWatch
+	idle  time.Duration
+}
+
+func (b *idleBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil && err != io.EOF && b.watch.isExpired() {
+		return n, ErrIdleTimeout
+	}
+	if n > 0 && !b.watch.isExpired() {
+		b.watch.timer.Reset(b.idle)
+	}
+	return n, err
+}
+
+func (b *idleBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.watch.timer.Stop()
+	b.watch.cancel()
+	return err
+}
+
+// RetryAfter returns the duration to wait according to the Retry-After
+// header, which may contain either a delay in seconds or an HTTP date
+// relative to c.Now(). It returns false if the header is missing or invalid.
+func RetryAfter(c Clock, h http.Header) (time.Duration, bool) {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if sec, err := strconv.ParseInt(v, 10, 32); err == nil {
+		if sec < 0 {
+			return 0, false
+		}
+		return time.Duration(sec) * time.Second, true
+	}
+	t, err := http.ParseTime(v)
+	if err != nil {
+		return 0, false
+	}
+	if d := c.Until(t); d > 0 {
+		return d, true
+	}
+	return 0, true
+}
+
+// RetryAfterTransport returns an http.RoundTripper which retries the
+// requests made with base at most maxRetries times, when the response has
+// the status 429 Too Many Requests or 503 Service Unavailable and a valid
+// Retry-After header.
+//
+// The wait is done with c.SleepContext, so it can be controlled with a Mock
+// and is interrupted when the request is canceled. Requests with a body are
+// only retried if their GetBody is set. A nil base uses http.DefaultTransport.
+func RetryAfterTransport(c Clock, base http.RoundTripper, maxRetries int) http.RoundTripper {
+	return &retryTransport{
+		clock:      c,
+		base:       transportOrDefault(base),
+		maxRetries: maxRetries,
+	}
+}
+
+type retryTransport struct {
+	clock      Clock
+	base       http.RoundTripper
+	maxRetries int
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for retry := 0; ; retry++ {
+		resp, err := t.base.RoundTrip(req)
+		if err != nil || retry >= t.maxRetries {
+			return resp, err
+		}
+		if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+			return resp, nil
+		}
+		d, ok := RetryAfter(t.clock, resp.Header)
+		if !ok || (req.Body != nil && req.Body != http.NoBody && req.GetBody == nil) {
+			return resp, nil
+		}
+		io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+		if err := t.clock.SleepContext(req.Context(), d); err != nil {
+			return nil, err
+		}
+		if req.GetBody != nil && req.Body != nil && req.Body != http.NoBody {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.WithContext(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// Middleware returns an HTTP middleware which associates the context of
+// each request with c, so that handlers can use FromContext.
+func Middleware(c Clock) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(Context(r.Context(), c)))
+		})
+	}
+}
+
+// OffsetMiddleware returns an HTTP middleware like Middleware, except that
+// a request with the OffsetHeader, such as "X-Clock-Offset: 36h", is
+// associated with Offset(c, d) for the duration d instead. Requests with an
+// invalid header are rejected with 400 Bad Request.
+//
+// OffsetMiddleware lets end-to-end tests shift the time of single requests
+// against a running server. It should not be used in production.
+func OffsetMiddleware(c Clock) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rc := c
+			if v := r.Header.Get(OffsetHeader); v != "" {
+				d, err := time.ParseDuration(v)
+				if err != nil {
+					http.Error(w, "invalid "+OffsetHeader+" header", http.StatusBadRequest)
+					return
+				}
+				rc = Offset(c, d)
+			}
+			next.ServeHTTP(w, r.WithContext(Context(r.Context(), rc)))
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package clock_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tilinna/clock"
+)
+
+func TestTransport(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	m := clock.NewMock(testTime)
+	client := &http.Client{Transport: clock.Transport(m, nil, 5*time.Second)}
+	errc := make(chan error, 1)
+	go func() {
+		resp, err := client.Get(srv.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		errc <- err
+	}()
+	m.BlockUntil(1)
+	m.Add(5 * time.Second)
+	err := <-errc
+	if uerr, ok := err.(*url.Error); !ok || uerr.Err != context.DeadlineExceeded {
+		t.Fatalf("want Get: %v, got: %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestIdleTimeoutTransport(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("x"))
+		w.(http.Flusher).Flush()
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	m := clock.NewMock(testTime)
+	client := &http.Client{Transport: clock.IdleTimeoutTransport(m, nil, 10*time.Second)}
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	m.Add(9 * time.Second)
+	// Reading the first byte rearms the idle timer.
+	if _, err := resp.Body.Read(make([]byte, 1)); err != nil {
+		t.Fatal(err)
+	}
+	errc := make(chan error, 1)
+	go func() {
+		_, err := ioutil.ReadAll(resp.Body)
+		errc <- err
+	}()
+	m.Add(9 * time.Second)
+	select {
+	case err := <-errc:
+		t.Fatalf("want ReadAll blocked before the idle timeout, got: %v", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+	m.Add(time.Second)
+	if got, want := <-errc, clock.ErrIdleTimeout; got != want {
+		t.Fatalf("want ReadAll: %v, got: %v", want, got)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	m := clock.NewMock(testTime)
+	for _, tt := range []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"", 0, false},
+		{"120", 2 * time.Minute, true},
+		{"-1", 0, false},
+		{"soon", 0, false},
+		{testTime.Add(time.Hour).UTC().Format(http.TimeFormat), time.Hour, true},
+		{testTime.Add(-time.Hour).UTC().Format(http.TimeFormat), 0, true},
+	} {
+		h := http.Header{}
+		if tt.value != "" {
+			h.Set("Retry-After", tt.value)
+		}
+		got, ok := clock.RetryAfter(m, h)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("RetryAfter(%q): want %v %v, got: %v %v", tt.value, tt.want, tt.ok, got, ok)
+		}
+	}
+}
+
+func TestRetryAfterTransport(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "120")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	m := clock.NewMock(testTime)
+	client := &http.Client{Transport: clock.RetryAfterTransport(m, nil, 3)}
+	type result struct {
+		resp *http.Response
+		err  error
+	}
+	resc := make(chan result, 1)
+	go func() {
+		resp, err := client.Get(srv.URL)
+		resc <- result{resp, err}
+	}()
+	m.BlockUntil(1)
+	if got, want := atomic.LoadInt32(&calls), int32(1); got != want {
+		t.Fatalf("want calls: %d, got: %d", want, got)
+	}
+	m.Add(2 * time.Minute)
+	res := <-resc
+	if res.err != nil {
+		t.Fatal(res.err)
+	}
+	res.resp.Body.Close()
+	if got, want := res.resp.StatusCode, http.StatusOK; got != want {
+		t.Fatalf("want StatusCode: %d, got: %d", want, got)
+	}
+	if got, want := atomic.LoadInt32(&calls), int32(2); got != want {
+		t.Fatalf("want calls: %d, got: %d", want, got)
+	}
+}
+
+func TestOffsetMiddleware(t *testing.T) {
+	m := clock.NewMock(testTime)
+	var got, fired time.Time
+	h := clock.OffsetMiddleware(m)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c := clock.FromContext(r.Context())
+		got = c.Now()
+		timer := c.NewTimer(0)
+		fired = <-timer.C
+	}))
+
+	for _, tt := range []struct {
+		header string
+		code   int
+		want   time.Time
+	}{
+		{"", http.StatusOK, testTime},
+		{"36h", http.StatusOK, testTime.Add(36 * time.Hour)},
+		{"-1m", http.StatusOK, testTime.Add(-time.Minute)},
+		{"tomorrow", http.StatusBadRequest, time.Time{}},
+	} {
+		got, fired = time.Time{}, time.Time{}
+		r := httptest.NewRequest("GET", "/", nil)
+		if tt.header != "" {
+			r.Header.Set(clock.OffsetHeader, tt.header)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != tt.code || !got.Equal(tt.want) || !fired.Equal(tt.want) {
+			t.Errorf("%q: want %d %v, got: %d %v (timer: %v)", tt.header, tt.code, tt.want, w.Code, got, fired)
+		}
+	}
+}
+
+func TestOffset(t *testing.T) {
+	m := clock.NewMock(testTime)
+	c := clock.Offset(m, time.Hour)
+	if got, want := c.Now(), testTime.Add(time.Hour); !got.Equal(want) {
+		t.Fatalf("want Now: %v, got: %v", want, got)
+	}
+	ctx, cancel := c.TimeoutContext(context.Background(), time.Minute)
+	defer cancel()
+	if got := clock.FromContext(ctx); got != c {
+		t.Fatalf("want FromContext: %v, got: %v", c, got)
+	}
+	if d, _ := ctx.Deadline(); !d.Equal(testTime.Add(time.Hour + time.Minute)) {
+		t.Fatalf("want Deadline: %v, got: %v", testTime.Add(time.Hour+time.Minute), d)
+	}
+	timer := c.NewTimerAt(testTime.Add(time.Hour + time.Second))
+	m.Add(time.Second)
+	if got, want := <-timer.C, testTime.Add(time.Hour+time.Second); !got.Equal(want) {
+		t.Fatalf("want <-timer.C: %v, got: %v", want, got)
+	}
+	ticker := c.NewTicker(time.Second)
+	defer ticker.Stop()
+	m.Add(time.Second)
+	if got, want := <-ticker.C, testTime.Add(time.Hour+2*time.Second); !got.Equal(want) {
+		t.Fatalf("want <-ticker.C: %v, got: %v", want, got)
+	}
+	aligned := c.NewAlignedTicker(time.Minute, 0)
+	defer aligned.Stop()
+	m.Add(58 * time.Second)
+	if got, want := <-aligned.C, testTime.Add(time.Hour+time.Minute); !got.Equal(want) {
+		t.Fatalf("want <-aligned.C: %v, got: %v", want, got)
+	}
+}
@@ -0,0 +1,38 @@
+package clock
+
+import "time"
+
+// Instant is a reading of a monotonic clock. Unlike time.Time, it has no
+// wall-clock component, so it can only be used for measuring elapsed time.
+//
+// Instants are only comparable with the Instants of the same Clock.
+type Instant struct {
+	d time.Duration // since an arbitrary reference point
+}
+
+// Add returns the Instant i+d.
+func (i Instant) Add(d time.Duration) Instant {
+	return Instant{i.d + d}
+}
+
+// Sub returns the duration i-u.
+func (i Instant) Sub(u Instant) time.Duration {
+	return i.d - u.d
+}
+
+// Before reports whether the Instant i is before u.
+func (i Instant) Before(u Instant) bool {
+	return i.d < u.d
+}
+
+// After reports whether the Instant i is after u.
+func (i Instant) After(u Instant) bool {
+	return i.d > u.d
+}
+
+// Equal reports whether i and u are the same Instant.
+func (i Instant) Equal(u Instant) bool {
+	return i.d == u.d
+}
+
+var realtimeEpoch = time.Now()
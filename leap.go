@@ -0,0 +1,85 @@
+package clock
+
+import "time"
+
+type leapSecond struct {
+	at    time.Time
+	smear time.Duration
+}
+
+// WithLeapSecond makes the Mock simulate a positive leap second inserted
+// just before at, as done by a system clock which repeats the last second
+// of the day: the second preceding at is shown twice by Now.
+//
+// Leap seconds affect only the wall-clock times returned by the Mock;
+// the durations of timers, tickers and Instants are not affected.
+// Instead, Now falls behind the timer deadlines by one second for each
+// simulated leap second. The times given to the Mock, such as the deadlines
+// of NewTimerAt and Set, are interpreted as wall-clock times.
+//
+// The time at should be after the current time of the Mock.
+func WithLeapSecond(at time.Time) MockOption {
+	return func(m *Mock) {
+		m.leaps = append(m.leaps, leapSecond{at: at})
+	}
+}
+
+// WithLeapSmear makes the Mock simulate a positive leap second at the time
+// at by smearing it linearly over a window centered on at. During the
+// window, Now runs slower than the timers, so that it has fallen behind by
+// one second at the end of the window. A window of 24*time.Hour matches the
+// leap smear of Google's public NTP servers.
+//
+// See WithLeapSecond for how leap seconds affect the Mock.
+func WithLeapSmear(at time.Time, window time.Duration) MockOption {
+	return func(m *Mock) {
+		m.leaps = append(m.leaps, leapSecond{at: at, smear: window})
+	}
+}
+
+// correction returns how much the wall clock is behind at the time t.
+func (l leapSecond) correction(t time.Time) time.Duration {
+	if l.smear <= 0 {
+		if t.Before(l.at) {
+			return 0
+		}
+		return time.Second
+	}
+	start := l.at.Add(-l.smear / 2)
+	switch {
+	case t.Before(start):
+		return 0
+	case !t.Before(start.Add(l.smear)):
+		return time.Second
+	}
+	return time.Duration(float64(time.Second) * float64(t.Sub(start)) / float64(l.smear))
+}
+
+// leapCorrection returns how much the wall clock of the Mock is behind
+// its timers at the time t.
+func (m *Mock) leapCorrection(t time.Time) time.Duration {
+	var d time.Duration
+	for _, l := range m.leaps {
+		d += l.correction(t)
+	}
+	return d
+}
+
+// internal converts a wall-clock time t to the time of the Mock timers.
+// Of the times shown twice by the wall clock, the first one is returned.
+func (m *Mock) internal(t time.Time) time.Time {
+	if len(m.leaps) == 0 {
+		return t
+	}
+	// Solve x-leapCorrection(x) = t. The iteration converges quickly,
+	// since the correction changes much slower than the time itself.
+	x := t
+	for i := 0; i < 10; i++ {
+		next := t.Add(m.leapCorrection(x))
+		if next.Equal(x) {
+			break
+		}
+		x = next
+	}
+	return x
+}
@@ -0,0 +1,64 @@
+package clock_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tilinna/clock"
+)
+
+var leapTime = time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestMock_WithLeapSecond(t *testing.T) {
+	m := clock.NewMock(leapTime.Add(-2*time.Second), clock.WithLeapSecond(leapTime))
+	tm := m.NewTimer(3 * time.Second)
+
+	var got []string
+	for i := 0; i < 4; i++ {
+		m.Add(time.Second)
+		got = append(got, m.Now().Format("15:04:05"))
+	}
+	want := []string{"23:59:59", "23:59:59", "00:00:00", "00:00:01"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("want %q, got: %q", want, got)
+		}
+	}
+	// Timer durations are not affected.
+	if got, want := (<-tm.C).Format("15:04:05"), "00:00:00"; got != want {
+		t.Fatalf("want timeout at %s, got: %s", want, got)
+	}
+	if got := m.Since(m.Now()); got != 0 {
+		t.Fatalf("want m.Since(m.Now()): 0, got: %s", got)
+	}
+}
+
+func TestMock_WithLeapSmear(t *testing.T) {
+	start := leapTime.Add(-13 * time.Hour)
+	m := clock.NewMock(start, clock.WithLeapSmear(leapTime, 24*time.Hour))
+	var elapsed time.Duration
+
+	tests := []struct {
+		d    time.Duration
+		want time.Duration // behind the true elapsed time
+	}{
+		{time.Hour, 0},
+		{13 * time.Hour, 500 * time.Millisecond},
+		{19 * time.Hour, 750 * time.Millisecond},
+		{25 * time.Hour, time.Second},
+		{48 * time.Hour, time.Second},
+	}
+	for _, tt := range tests {
+		m.Add(tt.d - elapsed)
+		elapsed = tt.d
+		if got, want := m.Now(), start.Add(tt.d-tt.want); !got.Equal(want) {
+			t.Fatalf("want m.Now() after %s: %s, got: %s", tt.d, want, got)
+		}
+	}
+
+	// Wall-clock deadlines are converted back to the timers' time.
+	tm := m.NewTimerAt(m.Now().Add(time.Hour))
+	if got, want := tm.Remaining(), time.Hour; got != want {
+		t.Fatalf("want tm.Remaining(): %s, got: %s", want, got)
+	}
+}
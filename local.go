@@ -0,0 +1,73 @@
+package clock
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// NextLocal returns the next time after c.Now() when the wall clock in loc
+// shows hour:min.
+//
+// Daylight saving time transitions are handled as follows. If hour:min is
+// skipped on some day, the time is shifted forward by the length of the
+// skipped period, so that 02:30 becomes 03:30 when the clocks are moved
+// from 02:00 to 03:00. If hour:min occurs twice on some day, only the first
+// occurrence is returned.
+func NextLocal(c Clock, hour, min int, loc *time.Location) time.Time {
+	if hour < 0 || hour > 23 || min < 0 || min > 59 {
+		panic(errors.New("invalid local time for NextLocal"))
+	}
+	now := c.Now()
+	y, m, d := now.In(loc).Date()
+	for {
+		if t := firstLocal(y, m, d, hour, min, loc); t.After(now) {
+			return t
+		}
+		d++
+	}
+}
+
+// firstLocal returns the first time when the wall clock in loc shows
+// hour:min on the given date, or the shifted time if hour:min is skipped.
+func firstLocal(y int, m time.Month, d, hour, min int, loc *time.Location) time.Time {
+	naive := time.Date(y, m, d, hour, min, 0, 0, time.UTC)
+	// The zone offsets a day before and after cover any transition.
+	_, before := naive.Add(-24 * time.Hour).In(loc).Zone()
+	_, after := naive.Add(24 * time.Hour).In(loc).Zone()
+	first := time.Time{}
+	for _, offset := range []int{before, after} {
+		t := naive.Add(-time.Duration(offset) * time.Second).In(loc)
+		if t.Hour() != hour || t.Minute() != min || t.Day() != naive.Day() {
+			continue
+		}
+		if first.IsZero() || t.Before(first) {
+			first = t
+		}
+	}
+	if first.IsZero() {
+		// Skipped by a transition; using the earlier offset shifts the time
+		// forward by the length of the skipped period.
+		first = naive.Add(-time.Duration(before) * time.Second).In(loc)
+	}
+	return first
+}
+
+// WaitUntilLocal waits until the next time when the wall clock in loc shows
+// hour:min, as returned by NextLocal(FromContext(ctx), hour, min, loc).
+//
+// If the context is done before that, WaitUntilLocal returns ctx.Err().
+func WaitUntilLocal(ctx context.Context, hour, min int, loc *time.Location) error {
+	c := FromContext(ctx)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	t := c.NewTimerAt(NextLocal(c, hour, min, loc))
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		t.Stop()
+		return ctx.Err()
+	}
+}
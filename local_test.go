@@ -0,0 +1,59 @@
+package clock_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tilinna/clock"
+)
+
+func TestNextLocal(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	tests := []struct {
+		now       time.Time
+		hour, min int
+		want      time.Time
+	}{
+		// Regular day.
+		{time.Date(2018, 3, 1, 12, 0, 0, 0, loc), 2, 30, time.Date(2018, 3, 2, 7, 30, 0, 0, time.UTC)},
+		{time.Date(2018, 3, 1, 1, 0, 0, 0, loc), 2, 30, time.Date(2018, 3, 1, 7, 30, 0, 0, time.UTC)},
+		// 02:30 is skipped, shifted to 03:30 EDT.
+		{time.Date(2018, 3, 10, 12, 0, 0, 0, loc), 2, 30, time.Date(2018, 3, 11, 7, 30, 0, 0, time.UTC)},
+		{time.Date(2018, 3, 11, 4, 0, 0, 0, loc), 2, 30, time.Date(2018, 3, 12, 6, 30, 0, 0, time.UTC)},
+		// 01:30 occurs twice, only the first (EDT) is used.
+		{time.Date(2018, 11, 3, 12, 0, 0, 0, loc), 1, 30, time.Date(2018, 11, 4, 5, 30, 0, 0, time.UTC)},
+		{time.Date(2018, 11, 4, 5, 45, 0, 0, time.UTC), 1, 30, time.Date(2018, 11, 5, 6, 30, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got := clock.NextLocal(clock.NewMock(tt.now), tt.hour, tt.min, loc)
+		if !got.Equal(tt.want) {
+			t.Errorf("NextLocal(%s, %02d:%02d): want %s, got: %s", tt.now, tt.hour, tt.min, tt.want.In(loc), got)
+		}
+	}
+}
+
+func TestWaitUntilLocal(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	m := clock.NewMock(time.Date(2018, 3, 10, 12, 0, 0, 0, loc))
+	ctx := clock.Context(context.Background(), m)
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- clock.WaitUntilLocal(ctx, 2, 30, loc)
+	}()
+	m.BlockUntil(1)
+	now, _ := m.AddNext()
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+	if got, want := now.In(loc).Format("15:04 MST"), "03:30 EDT"; got != want {
+		t.Fatalf("want wait until %s, got: %s", want, got)
+	}
+}
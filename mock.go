@@ -2,6 +2,7 @@ package clock
 
 import (
 	"context"
+	"math/rand"
 	"sync"
 	"time"
 )
@@ -12,9 +13,10 @@ type mockTimers interface {
 	reset(t *mockTimer)
 	next() *mockTimer
 	len() int
+	update(f func(t *mockTimer))
 }
 
-// Mock implements a Clock that only moves with Add, AddNext and Set.
+// Mock implements a Clock that only moves with Add, AddNext, Set and Jump.
 //
 // The clock can be suspended with Lock and resumed with Unlock.
 // While suspended, all attempts to use the API will block.
@@ -23,18 +25,102 @@ type mockTimers interface {
 // and release the Mutex only once during their execution.
 type Mock struct {
 	sync.Mutex
-	now time.Time
+	now  time.Time
+	mono time.Duration
 	mockTimers
+	pending []*mockTimer
+	onLock  func()
+	loc     *time.Location
+	leaps   []leapSecond
+	src     *lockedSource
+	rand    *rand.Rand
+	policy  DeadlinePolicy
+	jumped  time.Duration
+
+	timeline *timelineRecorder
+}
+
+// MockOption configures a Mock created with NewMock.
+type MockOption func(*Mock)
+
+// WithLocation makes the Mock return its current time in loc.
+// See Mock.SetLocation.
+func WithLocation(loc *time.Location) MockOption {
+	return func(m *Mock) {
+		m.loc = loc
+	}
+}
+
+// DeadlinePolicy defines how a Mock treats the deadline of a parent context
+// owned by another Clock, such as one from context.WithTimeout.
+// See DeadlineClock.
+type DeadlinePolicy int
+
+const (
+	// TranslateDeadlines translates the foreign deadline by the difference
+	// between the current times of the Clocks, so that the remaining time
+	// is kept. This is the default.
+	TranslateDeadlines DeadlinePolicy = iota
+
+	// IgnoreDeadlines ignores the foreign deadline. The cancellation of the
+	// parent context still propagates.
+	IgnoreDeadlines
+)
+
+// WithDeadlinePolicy sets the DeadlinePolicy of the Mock.
+func WithDeadlinePolicy(p DeadlinePolicy) MockOption {
+	return func(m *Mock) {
+		m.policy = p
+	}
 }
 
 // NewMock returns a new Mock with current time set to now.
 //
+// The random source returned by Rand is seeded with now.UnixNano(),
+// so Mocks created with the same time produce the same random values.
+//
 // Use Realtime to get the real-time Clock.
-func NewMock(now time.Time) *Mock {
-	return &Mock{
+func NewMock(now time.Time, opts ...MockOption) *Mock {
+	src := newLockedSource(now.UnixNano())
+	m := &Mock{
 		now:        now,
 		mockTimers: &timerHeap{},
+		src:        src,
+		rand:       rand.New(src),
+	}
+	for _, opt := range opts {
+		opt(m)
 	}
+	return m
+}
+
+// SetLocation changes the location of the times returned by the Mock,
+// including the times sent by its timers and tickers. A nil loc returns
+// the times in the location they were given to NewMock and Set.
+//
+// Changing the location does not change the current instant, so it can also
+// be used to simulate changes to the time zone database at runtime.
+func (m *Mock) SetLocation(loc *time.Location) {
+	m.lock()
+	defer m.Unlock()
+	m.loc = loc
+}
+
+// wall returns the current time as presented to the users of the Mock.
+// The Mock must be locked.
+func (m *Mock) wall() time.Time {
+	return m.wallAt(m.now)
+}
+
+// wallAt converts a time t of the Mock timers to a wall-clock time.
+func (m *Mock) wallAt(t time.Time) time.Time {
+	if len(m.leaps) > 0 {
+		t = t.Add(-m.leapCorrection(t))
+	}
+	if m.loc != nil {
+		return t.In(m.loc)
+	}
+	return t
 }
 
 // Add advances the current time by duration d and fires all expired timers.
@@ -42,7 +128,7 @@ func NewMock(now time.Time) *Mock {
 // Returns the new current time.
 // To increase predictability and speed, Tickers are ticked only once per call.
 func (m *Mock) Add(d time.Duration) time.Time {
-	m.Lock()
+	m.lock()
 	defer m.Unlock()
 	now, _ := m.set(m.now.Add(d))
 	return now
@@ -53,11 +139,12 @@ func (m *Mock) Add(d time.Duration) time.Time {
 //
 // Returns the new current time and the advanced duration.
 func (m *Mock) AddNext() (time.Time, time.Duration) {
-	m.Lock()
+	m.lock()
 	defer m.Unlock()
+	m.poll()
 	t := m.next()
 	if t == nil {
-		return m.now, 0
+		return m.wall(), 0
 	}
 	return m.set(t.deadline)
 }
@@ -67,19 +154,24 @@ func (m *Mock) AddNext() (time.Time, time.Duration) {
 // Returns the advanced duration.
 // To increase predictability and speed, Tickers are ticked only once per call.
 func (m *Mock) Set(t time.Time) time.Duration {
-	m.Lock()
+	m.lock()
 	defer m.Unlock()
-	_, d := m.set(t)
+	_, d := m.set(m.internal(t))
 	return d
 }
 
 func (m *Mock) set(now time.Time) (time.Time, time.Duration) {
+	m.poll()
 	cur := m.now
 	for {
 		t := m.next()
 		if t == nil || t.deadline.After(now) {
 			m.now = now
-			return m.now, m.now.Sub(cur)
+			d := m.now.Sub(cur)
+			if d > 0 {
+				m.mono += d
+			}
+			return m.wall(), d
 		}
 		m.now = t.deadline
 		if d := t.fire(); d == 0 {
@@ -94,59 +186,168 @@ func (m *Mock) set(now time.Time) (time.Time, time.Duration) {
 	}
 }
 
-// Len returns the number of active timers.
-func (m *Mock) Len() int {
+// Jump sets the current time to t without advancing the monotonic reading
+// returned by Instant, like a step of the wall clock by NTP or an operator.
+//
+// As with the timers of the standard time package, the remaining durations
+// of the active timers and tickers are not affected: their deadlines move
+// with the current time, and none of them are fired. So do the deadlines of
+// the contexts returned by DeadlineContext and TimeoutContext. The tickers
+// of NewAlignedTicker are realigned to the next boundary after t.
+func (m *Mock) Jump(t time.Time) {
+	m.lock()
+	defer m.Unlock()
+	d := m.internal(t).Sub(m.now)
+	m.now = m.now.Add(d)
+	m.jumped += d
+	m.update(func(t *mockTimer) {
+		if t.align != nil {
+			t.deadline = m.internal(t.align(m.wall()))
+		} else {
+			t.deadline = t.deadline.Add(d)
+		}
+	})
+}
+
+// Rand returns the random source owned by the Mock. Use it for jitter and
+// other randomized timing to make test runs reproducible.
+//
+// The returned Rand is safe for concurrent use, except for its Read method.
+func (m *Mock) Rand() *rand.Rand {
+	return m.rand
+}
+
+// Seed reinitializes the random source returned by Rand to a deterministic
+// state derived from seed.
+func (m *Mock) Seed(seed int64) {
+	m.src.Seed(seed)
+}
+
+// lock locks the Mock and calls the onLock hook, if any.
+func (m *Mock) lock() {
 	m.Lock()
+	if m.onLock != nil {
+		m.onLock()
+	}
+}
+
+// pend adds a stopped timer to the timers waiting to be rearmed.
+func (m *Mock) pend(t *mockTimer) {
+	m.pending = append(m.pending, t)
+}
+
+// unpend removes t from the timers waiting to be rearmed.
+func (m *Mock) unpend(t *mockTimer) {
+	for i, p := range m.pending {
+		if p == t {
+			m.pending = append(m.pending[:i], m.pending[i+1:]...)
+			return
+		}
+	}
+}
+
+// poll rearms the pending timers which are ready to continue.
+func (m *Mock) poll() {
+	pending := m.pending[:0]
+	for _, t := range m.pending {
+		if !t.rearm() {
+			pending = append(pending, t)
+		}
+	}
+	for i := len(pending); i < len(m.pending); i++ {
+		m.pending[i] = nil
+	}
+	m.pending = pending
+}
+
+// Len returns the number of active timers, including the FixedDelay
+// Tickers waiting for their previous tick to be received.
+func (m *Mock) Len() int {
+	m.lock()
 	defer m.Unlock()
-	return m.len()
+	return m.len() + len(m.pending)
+}
+
+// BlockUntil blocks until the Mock has at least n active timers, as
+// reported by Len. Use it to wait for the goroutines under test to start
+// their timers or sleeps before advancing the time.
+//
+// BlockUntil polls Len every millisecond of real time, so it only notices
+// new timers, not whether the goroutines are otherwise blocked. In a
+// testing/synctest bubble, synctest.Wait waits for the latter. See Synctest.
+func (m *Mock) BlockUntil(n int) {
+	for m.Len() < n {
+		time.Sleep(time.Millisecond)
+	}
 }
 
 // Now returns the current mocked time.
 func (m *Mock) Now() time.Time {
-	m.Lock()
+	m.lock()
+	defer m.Unlock()
+	return m.wall()
+}
+
+// Instant returns the current monotonic reading of the Mock. It is advanced
+// by Add, AddNext and Set, but not by Jump. Moving the current time
+// backwards with Set leaves it unchanged.
+func (m *Mock) Instant() Instant {
+	m.lock()
 	defer m.Unlock()
-	return m.now
+	return Instant{m.mono}
 }
 
 // Since returns the time elapsed since t.
 func (m *Mock) Since(t time.Time) time.Duration {
-	m.Lock()
+	m.lock()
 	defer m.Unlock()
-	return m.now.Sub(t)
+	return m.wall().Sub(t)
 }
 
 // Until returns the duration until t.
 func (m *Mock) Until(t time.Time) time.Duration {
-	m.Lock()
+	m.lock()
 	defer m.Unlock()
-	return t.Sub(m.now)
+	return t.Sub(m.wall())
 }
 
 // DeadlineContext implements Clock.
 func (m *Mock) DeadlineContext(parent context.Context, d time.Time) (context.Context, context.CancelFunc) {
-	m.Lock()
+	pd, own, ok := parentDeadline(m, parent, m.policy)
+	m.lock()
 	defer m.Unlock()
-	return m.deadlineContext(parent, d)
+	return m.deadlineContext(m, parent, pd, own, ok, d)
 }
 
 // TimeoutContext implements Clock.
 func (m *Mock) TimeoutContext(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
-	m.Lock()
+	pd, own, ok := parentDeadline(m, parent, m.policy)
+	m.lock()
 	defer m.Unlock()
-	return m.deadlineContext(parent, m.now.Add(timeout))
+	return m.deadlineContext(m, parent, pd, own, ok, m.wall().Add(timeout))
 }
 
-func (m *Mock) deadlineContext(parent context.Context, deadline time.Time) (context.Context, context.CancelFunc) {
-	cancelCtx, cancel := context.WithCancel(Context(parent, m))
-	if pd, ok := parent.Deadline(); ok && !pd.After(deadline) {
-		return cancelCtx, cancel
+// deadlineContext returns a context associated with c, whose deadline is
+// driven by the timers of m. The parent deadline pd, if ok, is given in the
+// time of c, as returned by parentDeadline. An earlier parent deadline owned
+// by another Clock is enforced by the timers of m.
+func (m *Mock) deadlineContext(c Clock, parent context.Context, pd time.Time, own, ok bool, deadline time.Time) (context.Context, context.CancelFunc) {
+	cancelCtx, cancel := context.WithCancel(Context(parent, c))
+	if ok && !pd.After(deadline) {
+		if own {
+			return cancelCtx, cancel
+		}
+		deadline = pd
 	}
 	ctx := &mockCtx{
 		Context:  cancelCtx,
+		clock:    c,
+		mock:     m,
 		done:     make(chan struct{}),
 		deadline: deadline,
+		jumped:   m.jumped,
 	}
-	t := m.newTimerFunc(deadline, nil)
+	t := m.newTimerFunc(m.internal(deadline), nil)
 	go func() {
 		select {
 		case <-t.C:
@@ -160,15 +361,30 @@ func (m *Mock) deadlineContext(parent context.Context, deadline time.Time) (cont
 	return ctx, cancel
 }
 
+// mockCtx is a context whose deadline was given when the Mock had jumped
+// by jumped. Later Jumps move the deadline, like the timer enforcing it.
 type mockCtx struct {
 	context.Context
+	clock    Clock
+	mock     *Mock
 	deadline time.Time
+	jumped   time.Duration
 	done     chan struct{}
 	err      error
 }
 
 func (ctx *mockCtx) Deadline() (time.Time, bool) {
-	return ctx.deadline, true
+	ctx.mock.Lock()
+	defer ctx.mock.Unlock()
+	return ctx.deadline.Add(ctx.mock.jumped - ctx.jumped), true
+}
+
+func (ctx *mockCtx) Value(key interface{}) interface{} {
+	if key == (deadlineKey{}) {
+		d, _ := ctx.Deadline()
+		return deadlineOwner{ctx.clock, d}
+	}
+	return ctx.Context.Value(key)
 }
 
 func (ctx *mockCtx) Done() <-chan struct{} {
@@ -1,6 +1,7 @@
 package clock_test
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
@@ -91,6 +92,18 @@ func TestMock_AddNext(t *testing.T) {
 	}
 }
 
+func TestMock_BlockUntil(t *testing.T) {
+	m := clock.NewMock(testTime)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		m.Sleep(time.Second)
+	}()
+	m.BlockUntil(1)
+	m.Add(time.Second)
+	<-done
+}
+
 func ExampleMock_AddNext() {
 	start := time.Now()
 	mock := clock.NewMock(start)
@@ -136,3 +149,237 @@ func ExampleNewMock() {
 	// Time is now 2018-01-01 10:00:25 +0000 UTC
 	// Timeout was 2018-01-01 10:00:15 +0000 UTC
 }
+
+func TestMock_Rand(t *testing.T) {
+	m1 := clock.NewMock(testTime)
+	m2 := clock.NewMock(testTime)
+	for i := 0; i < 10; i++ {
+		if got, want := m2.Rand().Int63(), m1.Rand().Int63(); got != want {
+			t.Fatalf("want same sequence for same start time: %d, got: %d", want, got)
+		}
+	}
+
+	m1.Seed(42)
+	m2.Seed(42)
+	d1 := time.Duration(m1.Rand().Int63n(int64(time.Minute)))
+	d2 := time.Duration(m2.Rand().Int63n(int64(time.Minute)))
+	tm1 := m1.NewTimer(d1)
+	tm2 := m2.NewTimer(d2)
+	m1.Add(time.Minute)
+	m2.Add(time.Minute)
+	if got, want := <-tm2.C, <-tm1.C; !got.Equal(want) {
+		t.Fatalf("want same deadline for same seed: %s, got: %s", want, got)
+	}
+
+	ctx := clock.Context(context.Background(), m1)
+	if got, want := clock.RandFromContext(ctx), m1.Rand(); got != want {
+		t.Fatalf("want RandFromContext: %p, got: %p", want, got)
+	}
+	if clock.RandFromContext(context.Background()) == nil {
+		t.Fatal("want non-nil realtime Rand")
+	}
+}
+
+func TestMock_NewTimerAt(t *testing.T) {
+	m := clock.NewMock(testTime)
+	ctx := clock.Context(context.Background(), m)
+
+	tm := clock.NewTimerAt(ctx, testTime.Add(time.Minute))
+	c := m.AfterAt(testTime.Add(2 * time.Minute))
+	done := make(chan struct{})
+	m.AfterFuncAt(testTime.Add(-time.Minute), func() { close(done) })
+	<-done // fires immediately
+
+	if got, want := m.Len(), 2; got != want {
+		t.Fatalf("want m.Len(): %d, got: %d", want, got)
+	}
+	m.Add(5 * time.Minute)
+	if got, want := <-tm.C, testTime.Add(time.Minute); !got.Equal(want) {
+		t.Fatalf("want timeout at %s, got: %s", want, got)
+	}
+	if got, want := <-c, testTime.Add(2*time.Minute); !got.Equal(want) {
+		t.Fatalf("want timeout at %s, got: %s", want, got)
+	}
+	m.SleepUntil(testTime) // returns immediately
+}
+
+func TestTimer_Deadline(t *testing.T) {
+	m := clock.NewMock(testTime)
+
+	tm := m.NewTimer(10 * time.Second)
+	tc := m.NewTicker(3 * time.Second)
+	m.Add(4 * time.Second)
+
+	if d, ok := tm.Deadline(); !ok || !d.Equal(testTime.Add(10*time.Second)) {
+		t.Fatalf("want tm.Deadline(): %s, got: %s %t", testTime.Add(10*time.Second), d, ok)
+	}
+	if got, want := tm.Remaining(), 6*time.Second; got != want {
+		t.Fatalf("want tm.Remaining(): %s, got: %s", want, got)
+	}
+	if d, ok := tc.Deadline(); !ok || !d.Equal(testTime.Add(6*time.Second)) {
+		t.Fatalf("want tc.Deadline(): %s, got: %s %t", testTime.Add(6*time.Second), d, ok)
+	}
+	if got, want := tc.Remaining(), 2*time.Second; got != want {
+		t.Fatalf("want tc.Remaining(): %s, got: %s", want, got)
+	}
+
+	m.Add(6 * time.Second)
+	if tm.Active() {
+		t.Fatal("want expired timer inactive")
+	}
+	if _, ok := tm.Deadline(); ok {
+		t.Fatal("want no deadline for expired timer")
+	}
+	if got := tm.Remaining(); got != 0 {
+		t.Fatalf("want tm.Remaining(): 0, got: %s", got)
+	}
+	if !tc.Active() {
+		t.Fatal("want ticker active")
+	}
+	tc.Stop()
+	if tc.Active() {
+		t.Fatal("want stopped ticker inactive")
+	}
+
+	rt := clock.Realtime().NewTimer(time.Hour)
+	if !rt.Active() || rt.Remaining() <= 59*time.Minute {
+		t.Fatalf("want realtime timer active for an hour, got: %t %s", rt.Active(), rt.Remaining())
+	}
+	rt.Stop()
+	if rt.Active() {
+		t.Fatal("want stopped realtime timer inactive")
+	}
+	rt.Reset(time.Millisecond)
+	<-rt.C
+	if rt.Active() {
+		t.Fatal("want expired realtime timer inactive")
+	}
+
+	rtc := clock.Realtime().NewTicker(time.Hour)
+	if d, ok := rtc.Deadline(); !ok || clock.Realtime().Until(d) <= 59*time.Minute {
+		t.Fatalf("want realtime ticker deadline in an hour, got: %s %t", d, ok)
+	}
+	rtc.Stop()
+	if rtc.Active() {
+		t.Fatal("want stopped realtime ticker inactive")
+	}
+}
+
+func TestTimer_Pause(t *testing.T) {
+	m := clock.NewMock(testTime)
+
+	tm := m.NewTimer(10 * time.Second)
+	m.Add(4 * time.Second)
+	if !tm.Pause() {
+		t.Fatal("want tm.Pause(): true")
+	}
+	if tm.Pause() {
+		t.Fatal("want tm.Pause() of a paused timer: false")
+	}
+	m.Add(time.Hour)
+	select {
+	case <-tm.C:
+		t.Fatal("want paused timer not to fire")
+	default:
+	}
+	if !tm.Paused() || !tm.Active() {
+		t.Fatal("want paused timer active")
+	}
+	if got, want := tm.Remaining(), 6*time.Second; got != want {
+		t.Fatalf("want tm.Remaining(): %s, got: %s", want, got)
+	}
+	if !tm.Resume() {
+		t.Fatal("want tm.Resume(): true")
+	}
+	if tm.Resume() {
+		t.Fatal("want tm.Resume() of a running timer: false")
+	}
+	_, d := m.AddNext()
+	if want := 6 * time.Second; d != want {
+		t.Fatalf("want AddNext(): %s, got: %s", want, d)
+	}
+	<-tm.C
+
+	tm.Reset(time.Second)
+	tm.Pause()
+	if !tm.Stop() {
+		t.Fatal("want Stop() of a paused timer: true")
+	}
+	if tm.Resume() {
+		t.Fatal("want Resume() of a stopped timer: false")
+	}
+
+	rt := clock.Realtime().NewTimer(time.Hour)
+	if !rt.Pause() {
+		t.Fatal("want realtime Pause(): true")
+	}
+	if r := rt.Remaining(); r <= 59*time.Minute || r > time.Hour {
+		t.Fatalf("want realtime Remaining() about an hour, got: %s", r)
+	}
+	if !rt.Resume() || rt.Paused() {
+		t.Fatal("want realtime Resume(): true")
+	}
+	rt.Stop()
+}
+
+func TestMock_Instant(t *testing.T) {
+	m := clock.NewMock(testTime)
+	start := clock.NowInstant(clock.Context(context.Background(), m))
+	timer := m.NewTimer(5 * time.Second)
+	m.Add(10 * time.Second)
+	<-timer.C
+	timer.Reset(5 * time.Second)
+	m.Jump(testTime.Add(-time.Hour)) // wall-clock jump backwards
+	if got, want := m.Now(), testTime.Add(-time.Hour); !got.Equal(want) {
+		t.Fatalf("want m.Now(): %s, got: %s", want, got)
+	}
+	if got, want := timer.Remaining(), 5*time.Second; got != want {
+		t.Fatalf("want timer.Remaining(): %s, got: %s", want, got)
+	}
+	m.Jump(testTime.Add(time.Hour)) // and forwards
+	if got, want := timer.Remaining(), 5*time.Second; got != want {
+		t.Fatalf("want timer.Remaining(): %s, got: %s", want, got)
+	}
+	select {
+	case <-timer.C:
+		t.Fatal("want timer not fired by Jump")
+	default:
+	}
+	now := m.Instant()
+	if got, want := now.Sub(start), 10*time.Second; got != want {
+		t.Fatalf("want elapsed: %s, got: %s", want, got)
+	}
+	if !start.Before(now) || !now.After(start) || !start.Add(now.Sub(start)).Equal(now) {
+		t.Fatalf("want %v before %v", start, now)
+	}
+
+	r := clock.Realtime()
+	i := r.Instant()
+	time.Sleep(time.Millisecond)
+	if d := r.Instant().Sub(i); d < time.Millisecond {
+		t.Fatalf("want realtime elapsed at least 1ms, got: %s", d)
+	}
+}
+
+func TestMock_Jump(t *testing.T) {
+	m := clock.NewMock(testTime)
+	ctx, cancel := m.TimeoutContext(context.Background(), time.Minute)
+	defer cancel()
+	ticker := m.NewAlignedTicker(10*time.Second, 0)
+	defer ticker.Stop()
+	m.Add(5 * time.Second)
+
+	m.Jump(testTime.Add(time.Hour + 2*time.Second))
+	if d, _ := ctx.Deadline(); !d.Equal(m.Now().Add(55 * time.Second)) {
+		t.Fatalf("want ctx.Deadline(): %s, got: %s", m.Now().Add(55*time.Second), d)
+	}
+	if d, _ := ticker.Deadline(); !d.Equal(testTime.Add(time.Hour + 10*time.Second)) {
+		t.Fatalf("want ticker.Deadline(): %s, got: %s", testTime.Add(time.Hour+10*time.Second), d)
+	}
+	m.Add(8 * time.Second)
+	if got, want := <-ticker.C, testTime.Add(time.Hour+10*time.Second); !got.Equal(want) {
+		t.Fatalf("want <-ticker.C: %s, got: %s", want, got)
+	}
+	m.Add(47 * time.Second)
+	<-ctx.Done()
+}
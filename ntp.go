@@ -0,0 +1,274 @@
+package clock
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	ntpPacketSize = 48
+	// ntpEpochOffset is the number of seconds between 1900 and 1970.
+	ntpEpochOffset = 2208988800
+	ntpVersion     = 4
+	ntpModeClient  = 3
+	ntpModeServer  = 4
+	// ntpSamples is the number of the latest samples NTPClock chooses from.
+	ntpSamples = 8
+	// ntpQueryTimeout is the default time NTPClock waits for a reply.
+	ntpQueryTimeout = 5 * time.Second
+)
+
+// toNTPTime returns t as an NTP timestamp.
+func toNTPTime(t time.Time) uint64 {
+	sec := uint64(t.Unix() + ntpEpochOffset)
+	frac := uint64(t.Nanosecond()) << 32 / uint64(time.Second)
+	return sec<<32 | frac
+}
+
+// fromNTPTime returns an NTP timestamp as a time.Time. The timestamps with
+// the most significant bit unset are taken to be after the year 2036.
+func fromNTPTime(v uint64) time.Time {
+	sec := int64(v >> 32)
+	if sec < 1<<31 {
+		sec += 1 << 32
+	}
+	nsec := int64((v & 0xffffffff) * uint64(time.Second) >> 32)
+	return time.Unix(sec-ntpEpochOffset, nsec)
+}
+
+// fromNTPShort returns an NTP short format value as a time.Duration.
+func fromNTPShort(v uint32) time.Duration {
+	return time.Duration(uint64(v) * uint64(time.Second) >> 16)
+}
+
+type ntpSample struct {
+	offset     time.Duration
+	delay      time.Duration
+	dispersion time.Duration
+}
+
+// NTPClock implements a Clock whose current time is corrected by the offset
+// measured by querying an SNTP server. The durations of its timers and
+// tickers are those of the base Clock, and only the absolute times are
+// corrected.
+//
+// The offset is zero until the first successful Sync.
+// Use Run to keep the offset up to date.
+type NTPClock struct {
+	offsetClock
+	server string
+
+	mu           sync.Mutex
+	samples      []ntpSample
+	best         ntpSample
+	queryTimeout time.Duration
+}
+
+// NewNTPClock returns a new NTPClock which corrects the time of the base
+// Clock by querying the SNTP server at the UDP address server, such as
+// "pool.ntp.org:123".
+func NewNTPClock(base Clock, server string) *NTPClock {
+	c := &NTPClock{
+		server:       server,
+		queryTimeout: ntpQueryTimeout,
+	}
+	c.offsetClock = offsetClock{
+		base:   base,
+		offset: c.Offset,
+		self:   c,
+	}
+	return c
+}
+
+// Offset returns the current correction to the time of the base Clock.
+func (c *NTPClock) Offset() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.best.offset
+}
+
+// Delay returns the round-trip delay of the query the current Offset is
+// based on.
+func (c *NTPClock) Delay() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.best.delay
+}
+
+// Dispersion returns an estimate of the maximum error of the current Offset.
+// It includes the root dispersion and half of the root delay reported by
+// the server, and half of the round-trip delay of the query.
+func (c *NTPClock) Dispersion() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.best.dispersion
+}
+
+// SetQueryTimeout sets how long Sync waits for a reply from the server,
+// measured with the base Clock. The default is 5 seconds.
+func (c *NTPClock) SetQueryTimeout(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.queryTimeout = d
+}
+
+// Run calls Sync every interval of the base Clock until the context is done,
+// and then returns ctx.Err(). Failed queries are ignored, and the previous
+// Offset stays in effect.
+func (c *NTPClock) Run(ctx context.Context, interval time.Duration) error {
+	for {
+		c.Sync(ctx)
+		if err := c.base.SleepContext(ctx, interval); err != nil {
+			return err
+		}
+	}
+}
+
+// Sync queries the server once and updates the Offset. It fails with
+// context.DeadlineExceeded if the server does not reply within the query
+// timeout. See SetQueryTimeout.
+//
+// Like NTP, the Offset is taken from the sample with the smallest round-trip
+// delay among the latest samples, as it is the least affected by
+// asymmetric network delays.
+func (c *NTPClock) Sync(ctx context.Context) error {
+	s, err := c.query(ctx)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.samples = append(c.samples, s)
+	if len(c.samples) > ntpSamples {
+		c.samples = c.samples[1:]
+	}
+	c.best = c.samples[0]
+	for _, s := range c.samples[1:] {
+		if s.delay < c.best.delay {
+			c.best = s
+		}
+	}
+	return nil
+}
+
+func (c *NTPClock) query(ctx context.Context) (ntpSample, error) {
+	c.mu.Lock()
+	timeout := c.queryTimeout
+	c.mu.Unlock()
+	ctx, cancel := c.base.TimeoutContext(ctx, timeout)
+	defer cancel()
+
+	// The context may follow any Clock, so its deadline is enforced by
+	// closing the connection below rather than by the dialer.
+	var d net.Dialer
+	conn, err := d.DialContext(hiddenDeadlineCtx{ctx}, "udp", c.server)
+	if err != nil {
+		return ntpSample{}, err
+	}
+	defer conn.Close()
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	req := make([]byte, ntpPacketSize)
+	req[0] = ntpVersion<<3 | ntpModeClient
+	t1 := c.base.Now()
+	binary.BigEndian.PutUint64(req[40:], toNTPTime(t1))
+	if _, err := conn.Write(req); err != nil {
+		return ntpSample{}, ctxErr(ctx, err)
+	}
+	resp := make([]byte, ntpPacketSize)
+	for {
+		n, err := conn.Read(resp)
+		if err != nil {
+			return ntpSample{}, ctxErr(ctx, err)
+		}
+		t4 := c.base.Now()
+		if n < ntpPacketSize || resp[0]&0x7 != ntpModeServer ||
+			binary.BigEndian.Uint64(resp[24:]) != binary.BigEndian.Uint64(req[40:]) {
+			continue // not a reply to our request
+		}
+		if resp[1] == 0 {
+			return ntpSample{}, errors.New("clock: kiss-o'-death from SNTP server " + string(resp[12:16]))
+		}
+		if resp[0]>>6 == 3 {
+			return ntpSample{}, errors.New("clock: SNTP server is not synchronized")
+		}
+		t2 := fromNTPTime(binary.BigEndian.Uint64(resp[32:]))
+		t3 := fromNTPTime(binary.BigEndian.Uint64(resp[40:]))
+		rootDelay := fromNTPShort(binary.BigEndian.Uint32(resp[4:]))
+		rootDispersion := fromNTPShort(binary.BigEndian.Uint32(resp[8:]))
+		// Use the durations from the base Clock to retain monotonic readings.
+		delay := t4.Sub(t1) - t3.Sub(t2)
+		if delay < 0 {
+			delay = 0
+		}
+		return ntpSample{
+			offset:     (t2.Sub(t1) + t3.Sub(t4)) / 2,
+			delay:      delay,
+			dispersion: rootDispersion + rootDelay/2 + delay/2,
+		}, nil
+	}
+}
+
+// ctxErr returns ctx.Err() if the context is done, or err otherwise.
+func ctxErr(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return err
+}
+
+// hiddenDeadlineCtx hides the deadline of its context.
+type hiddenDeadlineCtx struct {
+	context.Context
+}
+
+func (hiddenDeadlineCtx) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+// SNTPServer implements a minimal SNTP server, which answers the requests
+// with the current time of the Clock. It is useful for testing NTPClock
+// with a Mock.
+type SNTPServer struct {
+	Clock Clock
+}
+
+// Serve answers the SNTP requests received on pc until pc is closed.
+func (s *SNTPServer) Serve(pc net.PacketConn) error {
+	buf := make([]byte, 512)
+	for {
+		n, addr, err := pc.ReadFrom(buf)
+		if err != nil {
+			return err
+		}
+		received := s.Clock.Now()
+		if n < ntpPacketSize || buf[0]&0x7 != ntpModeClient {
+			continue
+		}
+		resp := make([]byte, ntpPacketSize)
+		resp[0] = buf[0]&0x38 | ntpModeServer // copy the version
+		resp[1] = 1                           // stratum: primary server
+		resp[2] = buf[2]                      // poll
+		resp[3] = 0xec                        // precision: 2^-20 seconds
+		copy(resp[12:16], "LOCL")
+		binary.BigEndian.PutUint64(resp[16:], toNTPTime(received))
+		copy(resp[24:32], buf[40:48])
+		binary.BigEndian.PutUint64(resp[32:], toNTPTime(received))
+		binary.BigEndian.PutUint64(resp[40:], toNTPTime(s.Clock.Now()))
+		if _, err := pc.WriteTo(resp, addr); err != nil {
+			return err
+		}
+	}
+}
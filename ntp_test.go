@@ -0,0 +1,149 @@
+package clock_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/tilinna/clock"
+)
+
+func TestNTPClock(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip(err)
+	}
+	defer pc.Close()
+	server := &clock.SNTPServer{Clock: clock.NewMock(testTime)}
+	go server.Serve(pc)
+
+	base := clock.NewMock(testTime.Add(-5 * time.Second))
+	c := clock.NewNTPClock(base, pc.LocalAddr().String())
+	if got := c.Offset(); got != 0 {
+		t.Fatalf("want c.Offset() before Sync: 0, got: %s", got)
+	}
+	if err := c.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := c.Offset(), 5*time.Second; got != want {
+		t.Fatalf("want c.Offset(): %s, got: %s", want, got)
+	}
+	if got := c.Delay(); got != 0 {
+		t.Fatalf("want c.Delay(): 0, got: %s", got)
+	}
+	if got, want := c.Now(), testTime; !got.Equal(want) {
+		t.Fatalf("want c.Now(): %s, got: %s", want, got)
+	}
+
+	tm := c.NewTimerAt(testTime.Add(time.Minute))
+	if got, want := tm.Remaining(), time.Minute; got != want {
+		t.Fatalf("want tm.Remaining(): %s, got: %s", want, got)
+	}
+	ctx, cancel := c.DeadlineContext(context.Background(), testTime.Add(time.Minute))
+	defer cancel()
+	if got := clock.FromContext(ctx); got != c {
+		t.Fatalf("want context Clock: %p, got: %p", c, got)
+	}
+	if d, _ := ctx.Deadline(); !d.Equal(testTime.Add(time.Minute)) {
+		t.Fatalf("want ctx.Deadline(): %s, got: %s", testTime.Add(time.Minute), d)
+	}
+	base.Add(time.Minute)
+	<-ctx.Done()
+	if got, want := <-tm.C, testTime.Add(time.Minute); !got.Equal(want) {
+		t.Fatalf("want <-tm.C: %s, got: %s", want, got)
+	}
+}
+
+func TestNTPClock_ticks(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip(err)
+	}
+	defer pc.Close()
+	m := clock.NewMock(testTime)
+	server := &clock.SNTPServer{Clock: m}
+	go server.Serve(pc)
+
+	base := clock.NewMock(testTime.Add(-5 * time.Second))
+	c := clock.NewNTPClock(base, pc.LocalAddr().String())
+	if err := c.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	tk := c.NewTicker(time.Second)
+	base.Add(time.Second)
+	if got, want := <-tk.C, testTime.Add(time.Second); !got.Equal(want) {
+		t.Fatalf("want <-tk.C: %s, got: %s", want, got)
+	}
+	tk.Stop()
+
+	at := c.NewAlignedTicker(time.Minute, 0)
+	defer at.Stop()
+	base.Add(59 * time.Second)
+	if got, want := <-at.C, testTime.Add(time.Minute); !got.Equal(want) {
+		t.Fatalf("want first <-at.C: %s, got: %s", want, got)
+	}
+	// Replace all the samples, so that the Offset shrinks by 2s and the
+	// base timer of the next tick expires 2s before the boundary.
+	m.Add(time.Minute - 2*time.Second)
+	for i := 0; i < 8; i++ {
+		if err := c.Sync(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := c.Offset(), 3*time.Second; got != want {
+		t.Fatalf("want c.Offset(): %s, got: %s", want, got)
+	}
+	base.Add(time.Minute)
+	base.BlockUntil(1)
+	select {
+	case got := <-at.C:
+		t.Fatalf("want no tick before the boundary, got: %s", got)
+	default:
+	}
+	base.Add(2 * time.Second)
+	if got, want := <-at.C, testTime.Add(2*time.Minute); !got.Equal(want) {
+		t.Fatalf("want second <-at.C: %s, got: %s", want, got)
+	}
+	base.Add(time.Minute)
+	if got, want := <-at.C, testTime.Add(3*time.Minute); !got.Equal(want) {
+		t.Fatalf("want third <-at.C: %s, got: %s", want, got)
+	}
+}
+
+func TestNTPClock_cancel(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip(err)
+	}
+	defer pc.Close() // never answers
+
+	c := clock.NewNTPClock(clock.Realtime(), pc.LocalAddr().String())
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if got, want := c.Sync(ctx), context.DeadlineExceeded; got != want {
+		t.Fatalf("want c.Sync(): %q, got: %q", want, got)
+	}
+}
+
+func TestNTPClock_queryTimeout(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip(err)
+	}
+	defer pc.Close() // never answers
+
+	m := clock.NewMock(testTime)
+	c := clock.NewNTPClock(m, pc.LocalAddr().String())
+	c.SetQueryTimeout(time.Second)
+	errc := make(chan error, 1)
+	go func() {
+		errc <- c.Sync(context.Background())
+	}()
+	m.BlockUntil(1)
+	m.Add(time.Second)
+	if got, want := <-errc, context.DeadlineExceeded; got != want {
+		t.Fatalf("want c.Sync(): %q, got: %q", want, got)
+	}
+}
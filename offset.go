@@ -0,0 +1,244 @@
+package clock
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// offsetClock is a Clock whose current time is ahead of its base Clock by
+// the duration returned by offset. The durations of its timers and tickers
+// are those of the base Clock, and only the absolute times are translated,
+// including the times sent on their channels.
+//
+// The contexts returned by DeadlineContext and TimeoutContext are associated
+// with self.
+type offsetClock struct {
+	base   Clock
+	offset func() time.Duration
+	self   Clock
+}
+
+func (c *offsetClock) After(d time.Duration) <-chan time.Time {
+	return c.NewTimer(d).C
+}
+
+func (c *offsetClock) AfterAt(t time.Time) <-chan time.Time {
+	return c.NewTimerAt(t).C
+}
+
+func (c *offsetClock) AfterFunc(d time.Duration, f func()) *Timer {
+	return c.base.AfterFunc(d, f)
+}
+
+func (c *offsetClock) AfterFuncAt(t time.Time, f func()) *Timer {
+	return c.base.AfterFuncAt(t.Add(-c.offset()), f)
+}
+
+// NewAlignedTicker re-reads the offset for every tick, so the ticks stay on
+// the boundaries of this Clock even if the offset changes. A tick whose base
+// timer expires before the boundary, because the offset has grown, is
+// rescheduled rather than sent early.
+func (c *offsetClock) NewAlignedTicker(period, offset time.Duration) *Ticker {
+	if period <= 0 {
+		panic(errors.New("non-positive interval for NewAlignedTicker"))
+	}
+	ch := make(chan time.Time, 1)
+	var (
+		mu      sync.Mutex
+		stopped bool
+		tm      *Timer
+	)
+	mu.Lock()
+	defer mu.Unlock()
+	next := nextAligned(c.Now(), period, offset)
+	tm = c.base.AfterFunc(c.Until(next), func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if stopped {
+			return
+		}
+		now := c.Now()
+		if now.Before(next) {
+			tm.Reset(next.Sub(now))
+			return
+		}
+		next = nextAligned(now, period, offset)
+		tm.Reset(next.Sub(now))
+		select {
+		case ch <- now:
+		default:
+		}
+	})
+	t := &Ticker{
+		C:         ch,
+		state:     tm.state,
+		mockTimer: tm.mockTimer,
+		onStop: func() {
+			mu.Lock()
+			stopped = true
+			mu.Unlock()
+		},
+	}
+	if tm.state != nil {
+		t.stop = func() { tm.Stop() }
+	}
+	return t
+}
+
+func (c *offsetClock) NewJitterTicker(period, jitter time.Duration, opts ...TickerOption) *Ticker {
+	return c.forward(c.base.NewJitterTicker(period, jitter, opts...))
+}
+
+func (c *offsetClock) NewTicker(d time.Duration) *Ticker {
+	return c.forward(c.base.NewTicker(d))
+}
+
+func (c *offsetClock) NewTickerWithOptions(d time.Duration, opts ...TickerOption) *Ticker {
+	return c.forward(c.base.NewTickerWithOptions(d, opts...))
+}
+
+// forward returns a copy of the base Ticker t whose channel receives the
+// ticks of t translated by the offset. The ticks are forwarded by a
+// goroutine, which exits when the returned Ticker is stopped. A FixedDelay
+// tick therefore counts as received once it has been forwarded.
+func (c *offsetClock) forward(t *Ticker) *Ticker {
+	ch := make(chan time.Time, 1)
+	done := make(chan struct{})
+	var once sync.Once
+	go func() {
+		for {
+			select {
+			case tick := <-t.C:
+				select {
+				case ch <- tick.Add(c.offset()):
+				case <-done:
+					return
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	ft := *t
+	ft.C = ch
+	ft.onStop = func() { once.Do(func() { close(done) }) }
+	return &ft
+}
+
+// NewTimer sends the current time of c, not that of the base Clock, when
+// the Timer expires. The time is sent from the function of a base AfterFunc
+// timer, so it arrives asynchronously.
+func (c *offsetClock) NewTimer(d time.Duration) *Timer {
+	ch := make(chan time.Time, 1)
+	t := c.base.AfterFunc(d, c.sendTime(ch))
+	t.C = ch
+	return t
+}
+
+func (c *offsetClock) NewTimerAt(t time.Time) *Timer {
+	ch := make(chan time.Time, 1)
+	tm := c.base.AfterFuncAt(t.Add(-c.offset()), c.sendTime(ch))
+	tm.C = ch
+	return tm
+}
+
+// sendTime returns a function which sends the current time to ch, unless
+// the previous time has not been received yet.
+func (c *offsetClock) sendTime(ch chan time.Time) func() {
+	return func() {
+		select {
+		case ch <- c.Now():
+		default:
+		}
+	}
+}
+
+func (c *offsetClock) Now() time.Time {
+	return c.base.Now().Add(c.offset())
+}
+
+func (c *offsetClock) Instant() Instant {
+	return c.base.Instant()
+}
+
+func (c *offsetClock) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+func (c *offsetClock) Sleep(d time.Duration) {
+	c.base.Sleep(d)
+}
+
+func (c *offsetClock) SleepContext(ctx context.Context, d time.Duration) error {
+	return c.base.SleepContext(ctx, d)
+}
+
+func (c *offsetClock) SleepUntil(t time.Time) {
+	c.base.SleepUntil(t.Add(-c.offset()))
+}
+
+func (c *offsetClock) Tick(d time.Duration) <-chan time.Time {
+	if d <= 0 {
+		return nil
+	}
+	return c.NewTicker(d).C
+}
+
+func (c *offsetClock) Until(t time.Time) time.Duration {
+	return t.Sub(c.Now())
+}
+
+func (c *offsetClock) DeadlineContext(parent context.Context, d time.Time) (context.Context, context.CancelFunc) {
+	offset := c.offset()
+	ctx, cancel := c.base.DeadlineContext(parent, d.Add(-offset))
+	return &offsetCtx{Context: ctx, base: c.base, clock: c.self, offset: offset}, cancel
+}
+
+func (c *offsetClock) TimeoutContext(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancel := c.base.TimeoutContext(parent, timeout)
+	return &offsetCtx{Context: ctx, base: c.base, clock: c.self, offset: c.offset()}, cancel
+}
+
+// offsetCtx associates a context of the base Clock with an offsetClock,
+// and translates its deadline if owned by the base Clock.
+type offsetCtx struct {
+	context.Context
+	base   Clock
+	clock  Clock
+	offset time.Duration
+}
+
+func (ctx *offsetCtx) Deadline() (time.Time, bool) {
+	d, ok := ctx.Context.Deadline()
+	if owner, _ := DeadlineClock(ctx.Context); ok && owner == ctx.base {
+		d = d.Add(ctx.offset)
+	}
+	return d, ok
+}
+
+func (ctx *offsetCtx) Value(key interface{}) interface{} {
+	switch key {
+	case clockKey{}:
+		return ctx.clock
+	case deadlineKey{}:
+		if owner, _ := DeadlineClock(ctx.Context); owner == ctx.base {
+			d, _ := ctx.Deadline()
+			return deadlineOwner{ctx.clock, d}
+		}
+	}
+	return ctx.Context.Value(key)
+}
+
+// Offset returns a Clock whose current time is ahead of c by the duration d.
+// The durations of its timers and tickers are those of c, and only the
+// absolute times are translated.
+func Offset(c Clock, d time.Duration) Clock {
+	oc := &offsetClock{
+		base:   c,
+		offset: func() time.Duration { return d },
+	}
+	oc.self = oc
+	return oc
+}
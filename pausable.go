@@ -0,0 +1,245 @@
+package clock
+
+import (
+	"context"
+	"time"
+)
+
+// pausablePoll is how often a running Pausable checks whether its FixedDelay
+// Tickers can continue.
+const pausablePoll = 10 * time.Millisecond
+
+// Pausable implements a Clock that follows a base Clock, but which can be
+// paused and resumed at runtime.
+//
+// While paused, Now stays constant and all timers, tickers, sleeps and
+// deadline contexts are held. When resumed, they continue with the remaining
+// durations they had when the Pausable was paused, and Now continues from
+// where it was paused.
+type Pausable struct {
+	base     Clock
+	mock     *Mock
+	offset   time.Duration // the total paused duration
+	pausedAt time.Time
+	paused   bool
+	wakeup   *Timer
+}
+
+// NewPausable returns a new running Pausable which follows the base Clock.
+func NewPausable(base Clock) *Pausable {
+	p := &Pausable{
+		base: base,
+		mock: NewMock(base.Now()),
+	}
+	p.mock.mockTimers = pausableTimers{
+		mockTimers: p.mock.mockTimers,
+		p:          p,
+	}
+	p.mock.onLock = p.advance
+	return p
+}
+
+// pausableTimers wakes up the Pausable when its timers are changed.
+type pausableTimers struct {
+	mockTimers
+	p *Pausable
+}
+
+func (h pausableTimers) start(t *mockTimer) {
+	h.mockTimers.start(t)
+	h.p.rearm()
+}
+
+func (h pausableTimers) reset(t *mockTimer) {
+	h.mockTimers.reset(t)
+	h.p.rearm()
+}
+
+// now returns the current time of the Pausable.
+// The mock must be locked.
+func (p *Pausable) now() time.Time {
+	if p.paused {
+		return p.pausedAt
+	}
+	return p.base.Now().Add(-p.offset)
+}
+
+// rearm sets up the wakeup for the next timer deadline.
+// The mock must be locked.
+func (p *Pausable) rearm() {
+	if p.paused {
+		if p.wakeup != nil {
+			p.wakeup.Stop()
+		}
+		return
+	}
+	t := p.mock.next()
+	if t == nil && len(p.mock.pending) == 0 {
+		if p.wakeup != nil {
+			p.wakeup.Stop()
+		}
+		return
+	}
+	d := pausablePoll
+	if t != nil {
+		if dd := t.deadline.Sub(p.now()); dd < d || len(p.mock.pending) == 0 {
+			d = dd
+		}
+	}
+	if p.wakeup == nil {
+		p.wakeup = p.base.AfterFunc(d, p.wake)
+	} else {
+		p.wakeup.Reset(d)
+	}
+}
+
+// advance moves the mock to the current time of the Pausable and fires all
+// expired timers. It is called whenever the mock is locked.
+func (p *Pausable) advance() {
+	if !p.paused {
+		p.mock.set(p.now())
+	}
+	p.rearm()
+}
+
+func (p *Pausable) wake() {
+	p.mock.lock()
+	p.mock.Unlock()
+}
+
+// Pause stops the Pausable. Does nothing if it is already paused.
+func (p *Pausable) Pause() {
+	p.mock.lock()
+	defer p.mock.Unlock()
+	if p.paused {
+		return
+	}
+	p.pausedAt = p.mock.now
+	p.paused = true
+	p.rearm()
+}
+
+// Resume continues a paused Pausable. Does nothing if it is not paused.
+func (p *Pausable) Resume() {
+	p.mock.lock()
+	defer p.mock.Unlock()
+	if !p.paused {
+		return
+	}
+	p.offset = p.base.Now().Sub(p.pausedAt)
+	p.paused = false
+	p.rearm()
+}
+
+// Paused reports whether the Pausable is paused.
+func (p *Pausable) Paused() bool {
+	p.mock.lock()
+	defer p.mock.Unlock()
+	return p.paused
+}
+
+// After implements Clock.
+func (p *Pausable) After(d time.Duration) <-chan time.Time {
+	return p.mock.After(d)
+}
+
+// AfterAt implements Clock.
+func (p *Pausable) AfterAt(t time.Time) <-chan time.Time {
+	return p.mock.AfterAt(t)
+}
+
+// AfterFunc implements Clock.
+func (p *Pausable) AfterFunc(d time.Duration, f func()) *Timer {
+	return p.mock.AfterFunc(d, f)
+}
+
+// AfterFuncAt implements Clock.
+func (p *Pausable) AfterFuncAt(t time.Time, f func()) *Timer {
+	return p.mock.AfterFuncAt(t, f)
+}
+
+// NewAlignedTicker implements Clock.
+func (p *Pausable) NewAlignedTicker(period, offset time.Duration) *Ticker {
+	return p.mock.NewAlignedTicker(period, offset)
+}
+
+// NewJitterTicker implements Clock.
+func (p *Pausable) NewJitterTicker(period, jitter time.Duration, opts ...TickerOption) *Ticker {
+	return p.mock.NewJitterTicker(period, jitter, opts...)
+}
+
+// NewTicker implements Clock.
+func (p *Pausable) NewTicker(d time.Duration) *Ticker {
+	return p.mock.NewTicker(d)
+}
+
+// NewTickerWithOptions implements Clock.
+func (p *Pausable) NewTickerWithOptions(d time.Duration, opts ...TickerOption) *Ticker {
+	return p.mock.NewTickerWithOptions(d, opts...)
+}
+
+// NewTimer implements Clock.
+func (p *Pausable) NewTimer(d time.Duration) *Timer {
+	return p.mock.NewTimer(d)
+}
+
+// NewTimerAt implements Clock.
+func (p *Pausable) NewTimerAt(t time.Time) *Timer {
+	return p.mock.NewTimerAt(t)
+}
+
+// Now implements Clock.
+func (p *Pausable) Now() time.Time {
+	return p.mock.Now()
+}
+
+// Instant implements Clock.
+func (p *Pausable) Instant() Instant {
+	return p.mock.Instant()
+}
+
+// Since implements Clock.
+func (p *Pausable) Since(t time.Time) time.Duration {
+	return p.mock.Since(t)
+}
+
+// Sleep implements Clock.
+func (p *Pausable) Sleep(d time.Duration) {
+	p.mock.Sleep(d)
+}
+
+// SleepContext implements Clock.
+func (p *Pausable) SleepContext(ctx context.Context, d time.Duration) error {
+	return p.mock.SleepContext(ctx, d)
+}
+
+// SleepUntil implements Clock.
+func (p *Pausable) SleepUntil(t time.Time) {
+	p.mock.SleepUntil(t)
+}
+
+// Tick implements Clock.
+func (p *Pausable) Tick(d time.Duration) <-chan time.Time {
+	return p.mock.Tick(d)
+}
+
+// Until implements Clock.
+func (p *Pausable) Until(t time.Time) time.Duration {
+	return p.mock.Until(t)
+}
+
+// DeadlineContext implements Clock.
+func (p *Pausable) DeadlineContext(parent context.Context, d time.Time) (context.Context, context.CancelFunc) {
+	pd, own, ok := parentDeadline(p, parent, TranslateDeadlines)
+	p.mock.lock()
+	defer p.mock.Unlock()
+	return p.mock.deadlineContext(p, parent, pd, own, ok, d)
+}
+
+// TimeoutContext implements Clock.
+func (p *Pausable) TimeoutContext(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	pd, own, ok := parentDeadline(p, parent, TranslateDeadlines)
+	p.mock.lock()
+	defer p.mock.Unlock()
+	return p.mock.deadlineContext(p, parent, pd, own, ok, p.mock.wall().Add(timeout))
+}
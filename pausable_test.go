@@ -0,0 +1,81 @@
+package clock_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tilinna/clock"
+)
+
+func TestPausable(t *testing.T) {
+	base := clock.NewMock(testTime)
+	p := clock.NewPausable(base)
+
+	tm := p.NewTimer(10 * time.Second)
+	ctx, cancel := p.TimeoutContext(context.Background(), 20*time.Second)
+	defer cancel()
+	if got := clock.FromContext(ctx); got != p {
+		t.Fatalf("want context Clock: %p, got: %p", p, got)
+	}
+
+	base.Add(4 * time.Second)
+	p.Pause()
+	if !p.Paused() {
+		t.Fatal("want paused")
+	}
+	base.Add(time.Hour)
+	if got, want := p.Now(), testTime.Add(4*time.Second); !got.Equal(want) {
+		t.Fatalf("want paused p.Now(): %s, got: %s", want, got)
+	}
+	if got, want := tm.Remaining(), 6*time.Second; got != want {
+		t.Fatalf("want tm.Remaining(): %s, got: %s", want, got)
+	}
+	select {
+	case <-tm.C:
+		t.Fatal("want timer held while paused")
+	case <-ctx.Done():
+		t.Fatal("want context held while paused")
+	default:
+	}
+
+	p.Resume()
+	base.Add(6 * time.Second)
+	if got, want := <-tm.C, testTime.Add(10*time.Second); !got.Equal(want) {
+		t.Fatalf("want timeout at %s, got: %s", want, got)
+	}
+	if got, want := p.Now(), testTime.Add(10*time.Second); !got.Equal(want) {
+		t.Fatalf("want p.Now(): %s, got: %s", want, got)
+	}
+
+	base.Add(10 * time.Second)
+	<-ctx.Done()
+	if got, want := ctx.Err(), context.DeadlineExceeded; got != want {
+		t.Fatalf("want ctx.Err(): %q, got: %q", want, got)
+	}
+}
+
+func TestPausable_realtime(t *testing.T) {
+	p := clock.NewPausable(clock.Realtime())
+	p.Pause()
+	now := p.Now()
+	c := p.After(time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	select {
+	case <-c:
+		t.Fatal("want timer held while paused")
+	default:
+	}
+	if !p.Now().Equal(now) {
+		t.Fatal("want Now() constant while paused")
+	}
+	p.Resume()
+	select {
+	case <-c:
+	case <-time.After(time.Second):
+		t.Fatal("want timer to fire after Resume")
+	}
+	if err := p.SleepContext(context.Background(), time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,56 @@
+package clock
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// lockedSource is a rand.Source64 that is safe for concurrent use.
+type lockedSource struct {
+	mu  sync.Mutex
+	src rand.Source64
+}
+
+func newLockedSource(seed int64) *lockedSource {
+	return &lockedSource{
+		src: rand.NewSource(seed).(rand.Source64),
+	}
+}
+
+func (s *lockedSource) Int63() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Int63()
+}
+
+func (s *lockedSource) Uint64() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Uint64()
+}
+
+func (s *lockedSource) Seed(seed int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.src.Seed(seed)
+}
+
+var realtimeRand = rand.New(newLockedSource(time.Now().UnixNano()))
+
+type randClock interface {
+	Rand() *rand.Rand
+}
+
+// RandFromContext returns the random source of the Clock associated with
+// the context. For a Mock, this is Mock.Rand(). Otherwise, a shared source
+// seeded with the current time is returned.
+//
+// The returned Rand is safe for concurrent use, except for its Read method.
+func RandFromContext(ctx context.Context) *rand.Rand {
+	if c, ok := FromContext(ctx).(randClock); ok {
+		return c.Rand()
+	}
+	return realtimeRand
+}
@@ -0,0 +1,45 @@
+//go:build go1.21
+// +build go1.21
+
+package clock
+
+import (
+	"context"
+	"log/slog"
+)
+
+// NewSlogHandler returns a slog.Handler which sets the time of each record
+// to FromContext(ctx).Now() before passing it to h. Records with a zero
+// time are passed unchanged.
+//
+// With a Mock associated with the context, the log output has the mocked
+// timestamps, which keeps golden log files stable in tests.
+func NewSlogHandler(h slog.Handler) slog.Handler {
+	return &slogHandler{h}
+}
+
+type slogHandler struct {
+	h slog.Handler
+}
+
+func (h *slogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.h.Enabled(ctx, level)
+}
+
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	if !r.Time.IsZero() {
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		r.Time = FromContext(ctx).Now()
+	}
+	return h.h.Handle(ctx, r)
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &slogHandler{h.h.WithAttrs(attrs)}
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	return &slogHandler{h.h.WithGroup(name)}
+}
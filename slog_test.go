@@ -0,0 +1,53 @@
+//go:build go1.21
+// +build go1.21
+
+package clock_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/tilinna/clock"
+)
+
+func TestNewSlogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(clock.NewSlogHandler(slog.NewTextHandler(&buf, nil))).With("a", 1).WithGroup("g")
+
+	m := clock.NewMock(testTime)
+	ctx := clock.Context(context.Background(), m)
+	logger.InfoContext(ctx, "first", "b", 2)
+	m.Add(time.Second)
+	logger.InfoContext(ctx, "second")
+
+	want := "time=" + testTime.Format("2006-01-02T15:04:05.000Z07:00") + " level=INFO msg=first a=1 g.b=2\n" +
+		"time=" + testTime.Add(time.Second).Format("2006-01-02T15:04:05.000Z07:00") + " level=INFO msg=second a=1\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("want:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func ExampleNewSlogHandler() {
+	h := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				a.Value = slog.StringValue(a.Value.Time().UTC().Format(time.RFC3339))
+			}
+			return a
+		},
+	})
+	logger := slog.New(clock.NewSlogHandler(h))
+
+	m := clock.NewMock(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	ctx := clock.Context(context.Background(), m)
+	logger.InfoContext(ctx, "started")
+	m.Add(time.Minute)
+	logger.InfoContext(ctx, "done")
+	// Output:
+	// time=2024-01-02T03:04:05Z level=INFO msg=started
+	// time=2024-01-02T03:05:05Z level=INFO msg=done
+}
@@ -0,0 +1,53 @@
+//go:build go1.25
+// +build go1.25
+
+package clock
+
+import (
+	"testing/synctest"
+	"time"
+)
+
+// Synctest is a Clock for tests run in a testing/synctest bubble, where the
+// standard time package is virtualized. It is the real-time Clock with
+// Mock-like helpers for advancing the time of the bubble, so code written
+// against Clock works unchanged both with a Mock and inside a bubble.
+//
+// To migrate a Mock based test, run it with synctest.Test and replace:
+//
+//   - NewMock(t) with NewSynctest(). The bubble starts at
+//     2000-01-01 00:00:00 UTC.
+//   - m.Add(d) with s.Add(d).
+//   - m.Set(t) with s.Add(time.Until(t)). The time cannot move backwards.
+//   - m.BlockUntil(n) and polling m.Len() with s.Wait(), which waits until
+//     the other goroutines are durably blocked rather than for n timers.
+//   - m.AddNext() with s.Add of the known duration. The deadlines of the
+//     runtime timers are not visible.
+//
+// Unlike with a Mock, Tickers tick at each of their deadlines during Add.
+type Synctest struct {
+	Clock
+}
+
+// NewSynctest returns a new Synctest. It must be used inside a
+// testing/synctest bubble.
+func NewSynctest() *Synctest {
+	return &Synctest{Realtime()}
+}
+
+// Add advances the time of the bubble by duration d, and waits until the
+// other goroutines in the bubble are durably blocked, so that all expired
+// timers have been handled.
+//
+// Returns the new current time.
+func (s *Synctest) Add(d time.Duration) time.Time {
+	time.Sleep(d)
+	synctest.Wait()
+	return time.Now()
+}
+
+// Wait is synctest.Wait. It blocks until the other goroutines in the bubble
+// are durably blocked, such as on a timer or a sleep.
+func (s *Synctest) Wait() {
+	synctest.Wait()
+}
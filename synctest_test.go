@@ -0,0 +1,47 @@
+//go:build go1.25
+// +build go1.25
+
+package clock_test
+
+import (
+	"context"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/tilinna/clock"
+)
+
+func TestSynctest(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		s := clock.NewSynctest()
+		start := s.Now()
+		ctx := clock.Context(context.Background(), s)
+
+		woke := make(chan time.Time, 1)
+		go func() {
+			clock.Sleep(ctx, time.Minute)
+			woke <- clock.Now(ctx)
+		}()
+		s.Wait()
+		if len(woke) != 0 {
+			t.Fatal("want Sleep blocked")
+		}
+		if got, want := s.Add(time.Minute), start.Add(time.Minute); !got.Equal(want) {
+			t.Fatalf("want Add: %v, got: %v", want, got)
+		}
+		if len(woke) == 0 {
+			t.Fatal("want Sleep done after Add")
+		}
+		if got, want := <-woke, start.Add(time.Minute); !got.Equal(want) {
+			t.Fatalf("want woke: %v, got: %v", want, got)
+		}
+
+		tctx, cancel := clock.TimeoutContext(ctx, time.Second)
+		defer cancel()
+		s.Add(time.Second)
+		if got, want := tctx.Err(), context.DeadlineExceeded; got != want {
+			t.Fatalf("want Err: %v, got: %v", want, got)
+		}
+	})
+}
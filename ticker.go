@@ -2,6 +2,8 @@ package clock
 
 import (
 	"errors"
+	"math/rand"
+	"sync"
 	"time"
 )
 
@@ -9,55 +11,381 @@ import (
 type Ticker struct {
 	C      <-chan time.Time
 	ticker *time.Ticker
+	stop   func()
+	state  *realtimeState
+	onStop func()
 	*mockTimer
 }
 
+// TickerOption configures a Ticker created with NewTickerWithOptions or
+// NewJitterTicker.
+type TickerOption func(*tickerOptions)
+
+type tickerOptions struct {
+	fixedDelay bool
+	immediate  bool
+	maxTicks   int
+}
+
+func newTickerOptions(opts []TickerOption) tickerOptions {
+	var o tickerOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// FixedDelay makes the Ticker measure each period from when the previous
+// tick was received, instead of ticking at a fixed rate. No ticks are
+// dropped, since the next period starts only after the previous tick has
+// been received.
+//
+// With a Mock, the reception is noticed at the beginning of the next Add,
+// AddNext or Set call.
+func FixedDelay() TickerOption {
+	return func(o *tickerOptions) {
+		o.fixedDelay = true
+	}
+}
+
+// TickImmediately makes the Ticker send the first tick on creation.
+func TickImmediately() TickerOption {
+	return func(o *tickerOptions) {
+		o.immediate = true
+	}
+}
+
+// MaxTicks stops the Ticker after n ticks have been sent.
+// A non-positive n means no limit.
+func MaxTicks(n int) TickerOption {
+	return func(o *tickerOptions) {
+		o.maxTicks = n
+	}
+}
+
 // NewTicker returns a new Ticker containing a channel that will send the
 // current time with a period specified by the duration d.
 func (m *Mock) NewTicker(d time.Duration) *Ticker {
-	m.Lock()
+	m.lock()
 	defer m.Unlock()
 	if d <= 0 {
 		panic(errors.New("non-positive interval for NewTicker"))
 	}
-	return m.newTicker(d)
+	return m.newTicker(m.now.Add(d), fixedInterval(d), tickerOptions{})
+}
+
+// NewTickerWithOptions returns a new Ticker like NewTicker, configured by
+// opts.
+func (m *Mock) NewTickerWithOptions(d time.Duration, opts ...TickerOption) *Ticker {
+	m.lock()
+	defer m.Unlock()
+	if d <= 0 {
+		panic(errors.New("non-positive interval for NewTickerWithOptions"))
+	}
+	return m.newTicker(m.now.Add(d), fixedInterval(d), newTickerOptions(opts))
+}
+
+// NewAlignedTicker returns a new Ticker that ticks at the multiples of period
+// since the zero time, shifted by offset. For example, a period of time.Minute
+// ticks at every full minute, and an additional offset of 15*time.Second at
+// 15 seconds past every full minute.
+//
+// Like time.Time.Truncate, the alignment operates on the absolute time and
+// ignores the location of the current time.
+func (m *Mock) NewAlignedTicker(period, offset time.Duration) *Ticker {
+	m.lock()
+	defer m.Unlock()
+	if period <= 0 {
+		panic(errors.New("non-positive interval for NewAlignedTicker"))
+	}
+	t := m.newTicker(m.internal(nextAligned(m.wall(), period, offset)), fixedInterval(period), tickerOptions{})
+	t.align = func(now time.Time) time.Time {
+		return nextAligned(now, period, offset)
+	}
+	return t
 }
 
 // Tick is a convenience wrapper for NewTicker providing access to the ticking
 // channel only.
 func (m *Mock) Tick(d time.Duration) <-chan time.Time {
-	m.Lock()
+	m.lock()
 	defer m.Unlock()
 	if d <= 0 {
 		return nil
 	}
-	return m.newTicker(d).C
+	return m.newTicker(m.now.Add(d), fixedInterval(d), tickerOptions{}).C
 }
 
-func (m *Mock) newTicker(d time.Duration) *Ticker {
+// NewJitterTicker returns a new Ticker like NewTicker, except that each
+// period is chosen randomly between period-jitter and period+jitter.
+// The random values are taken from Rand, so that the ticks are reproducible
+// with Seed.
+func (m *Mock) NewJitterTicker(period, jitter time.Duration, opts ...TickerOption) *Ticker {
+	m.lock()
+	defer m.Unlock()
+	interval := jitterInterval(m.rand, period, jitter)
+	return m.newTicker(m.now.Add(interval()), interval, newTickerOptions(opts))
+}
+
+func (m *Mock) newTicker(first time.Time, interval func() time.Duration, o tickerOptions) *Ticker {
 	c := make(chan time.Time, 1)
 	t := &Ticker{
 		C:         c,
-		mockTimer: newMockTimer(m, m.now.Add(d)),
+		mockTimer: newMockTimer(m, first),
 	}
-	t.fire = func() time.Duration {
+	m.created(t.mockTimer, "Ticker")
+	ticks := 0
+	send := func() time.Duration {
+		m.record(t.mockTimer, "fired")
 		select {
-		case c <- m.now:
+		case c <- m.wall():
 		default:
 		}
-		return d
+		ticks++
+		if o.maxTicks > 0 && ticks >= o.maxTicks {
+			return 0
+		}
+		if o.fixedDelay {
+			m.pend(t.mockTimer)
+			return 0
+		}
+		return interval()
+	}
+	t.fire = send
+	if o.fixedDelay {
+		t.rearm = func() bool {
+			if len(c) > 0 {
+				return false
+			}
+			t.deadline = m.now.Add(interval())
+			m.start(t.mockTimer)
+			m.record(t.mockTimer, "reset")
+			return true
+		}
+	}
+	if o.immediate && send() == 0 {
+		return t
 	}
 	m.start(t.mockTimer)
 	return t
 }
 
+// nextAligned returns the first time after t which is a multiple of period
+// since the zero time, shifted by offset.
+func nextAligned(t time.Time, period, offset time.Duration) time.Time {
+	return t.Add(-offset).Truncate(period).Add(offset + period)
+}
+
+func fixedInterval(d time.Duration) func() time.Duration {
+	return func() time.Duration {
+		return d
+	}
+}
+
+// jitterInterval returns a function which returns random durations between
+// period-jitter and period+jitter.
+func jitterInterval(r *rand.Rand, period, jitter time.Duration) func() time.Duration {
+	if period <= 0 {
+		panic(errors.New("non-positive interval for NewJitterTicker"))
+	}
+	if jitter < 0 || jitter >= period {
+		panic(errors.New("jitter out of range for NewJitterTicker"))
+	}
+	return func() time.Duration {
+		if jitter == 0 {
+			return period
+		}
+		return period - jitter + time.Duration(r.Int63n(int64(2*jitter)+1))
+	}
+}
+
+// nextInterval returns a function which returns the first deadline after
+// its argument, counting the intervals from the time of the first call.
+// Like with the Mock, missed deadlines are skipped.
+func nextInterval(interval func() time.Duration) func(now time.Time) time.Time {
+	var deadline time.Time
+	return func(now time.Time) time.Time {
+		if deadline.IsZero() {
+			deadline = now
+		}
+		if !deadline.After(now) {
+			d := interval()
+			deadline = deadline.Add((now.Sub(deadline)/d + 1) * d)
+		}
+		return deadline
+	}
+}
+
+// nextDelay returns a function which returns its argument delayed by interval.
+func nextDelay(interval func() time.Duration) func(now time.Time) time.Time {
+	return func(now time.Time) time.Time {
+		return now.Add(interval())
+	}
+}
+
+// newIntervalTicker returns a realtime Ticker which ticks after the durations
+// returned by interval.
+func newIntervalTicker(interval func() time.Duration, o tickerOptions) *Ticker {
+	if o.fixedDelay {
+		return newRealtimeTicker(o, false, nextDelay(interval))
+	}
+	return newRealtimeTicker(o, false, nextInterval(interval))
+}
+
+// maxRealtimeWait limits how long a realtime ticker sleeps before it checks
+// the wall clock again, so that the ticks are re-aligned after wall-clock
+// jumps.
+const maxRealtimeWait = time.Minute
+
+// newRealtimeTicker returns a Ticker which ticks when the current time
+// reaches the time returned by next. The next function is called with the
+// current time, first on creation and then after each tick.
+//
+// If realign is set, next is called also when the wall clock jumps backwards.
+func newRealtimeTicker(o tickerOptions, realign bool, next func(now time.Time) time.Time) *Ticker {
+	var c chan time.Time
+	if o.fixedDelay {
+		c = make(chan time.Time)
+	} else {
+		c = make(chan time.Time, 1)
+	}
+	done := make(chan struct{})
+	var once sync.Once
+	state := &realtimeState{active: true}
+	var deadline time.Time
+	if !o.immediate {
+		deadline = next(time.Now())
+		state.deadline = deadline
+	}
+	t := &Ticker{
+		C: c,
+		stop: func() {
+			once.Do(func() { close(done) })
+		},
+		state: state,
+	}
+	go func() {
+		ticks := 0
+		// send returns false when the ticker should stop.
+		send := func(now time.Time) bool {
+			if o.fixedDelay {
+				state.update(time.Time{})
+				select {
+				case c <- now:
+				case <-done:
+					return false
+				}
+			} else {
+				select {
+				case c <- now:
+				default:
+				}
+			}
+			ticks++
+			if o.maxTicks > 0 && ticks >= o.maxTicks {
+				state.set(time.Time{}, false)
+				return false
+			}
+			return true
+		}
+		if o.immediate {
+			if !send(time.Now()) {
+				return
+			}
+			deadline = next(time.Now())
+			state.update(deadline)
+		}
+		// Strip the monotonic clock readings to compare wall-clock times.
+		last := time.Now().Round(0)
+		timer := time.NewTimer(maxRealtimeWait)
+		defer timer.Stop()
+		for {
+			wait := deadline.Sub(time.Now())
+			if wait > maxRealtimeWait {
+				wait = maxRealtimeWait
+			}
+			timer.Reset(wait)
+			select {
+			case <-timer.C:
+			case <-done:
+				return
+			}
+			now := time.Now()
+			wall := now.Round(0)
+			if realign && wall.Before(last) {
+				// The wall clock has jumped backwards.
+				deadline = next(now)
+				state.update(deadline)
+			}
+			last = wall
+			if now.Before(deadline) {
+				continue
+			}
+			if !send(now) {
+				return
+			}
+			deadline = next(time.Now())
+			state.update(deadline)
+		}
+	}()
+	return t
+}
+
 // Stop turns off a ticker. After Stop, no more ticks will be sent.
 func (t *Ticker) Stop() {
+	if t.onStop != nil {
+		t.onStop()
+	}
 	if t.ticker != nil {
 		t.ticker.Stop()
+		t.state.set(time.Time{}, false)
 		return
 	}
-	t.mock.Lock()
+	if t.stop != nil {
+		t.stop()
+		t.state.set(time.Time{}, false)
+		return
+	}
+	t.mock.lock()
 	defer t.mock.Unlock()
+	if t.mockActive() {
+		t.mock.record(t.mockTimer, "stopped")
+	}
 	t.mock.stop(t.mockTimer)
+	t.mock.unpend(t.mockTimer)
+}
+
+// Deadline returns the time of the next tick.
+// It returns false if the ticker has been stopped or the time of the next
+// tick is not yet known, as with a FixedDelay Ticker whose previous tick has
+// not been received.
+func (t *Ticker) Deadline() (time.Time, bool) {
+	if t.state != nil {
+		return t.state.deadlineOf()
+	}
+	t.mock.lock()
+	defer t.mock.Unlock()
+	return t.mockDeadline()
+}
+
+// Remaining returns the duration until the next tick.
+// It returns zero if the time of the next tick is not known.
+func (t *Ticker) Remaining() time.Duration {
+	if t.state != nil {
+		return t.state.remaining()
+	}
+	t.mock.lock()
+	defer t.mock.Unlock()
+	return t.mockRemaining()
+}
+
+// Active reports whether the ticker will send more ticks.
+func (t *Ticker) Active() bool {
+	if t.state != nil {
+		_, active := t.state.get()
+		return active
+	}
+	t.mock.lock()
+	defer t.mock.Unlock()
+	return t.mockActive()
 }
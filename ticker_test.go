@@ -0,0 +1,167 @@
+package clock_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/tilinna/clock"
+)
+
+func TestMock_NewAlignedTicker(t *testing.T) {
+	m := clock.NewMock(testTime.Add(7 * time.Second))
+
+	tc := m.NewAlignedTicker(10*time.Second, 0)
+	defer tc.Stop()
+	tco := m.NewAlignedTicker(10*time.Second, 23*time.Second)
+	defer tco.Stop()
+
+	test := func(C <-chan time.Time, want time.Duration) {
+		if got := (<-C).Sub(testTime); got != want {
+			t.Errorf("want tick at t+%s, got: t+%s", want, got)
+		}
+	}
+
+	m.AddNext()
+	test(tc.C, 10*time.Second)
+	m.AddNext()
+	test(tco.C, 13*time.Second)
+
+	m.Add(30 * time.Second) // ticks only once per call
+	test(tc.C, 20*time.Second)
+	test(tco.C, 23*time.Second)
+	m.AddNext()
+	test(tc.C, 50*time.Second)
+}
+
+func TestRealtime_NewAlignedTicker(t *testing.T) {
+	const period = 20 * time.Millisecond
+	tc := clock.Realtime().NewAlignedTicker(period, 0)
+	defer tc.Stop()
+	select {
+	case <-tc.C:
+	case <-time.After(time.Second):
+		t.Fatal("want a tick within a second")
+	}
+}
+
+func TestMock_NewTicker_options(t *testing.T) {
+	m := clock.NewMock(testTime)
+
+	test := func(C <-chan time.Time, want time.Duration) {
+		select {
+		case now := <-C:
+			if got := now.Sub(testTime); got != want {
+				t.Errorf("want tick at t+%s, got: t+%s", want, got)
+			}
+		default:
+			t.Errorf("want tick at t+%s, got none", want)
+		}
+	}
+	none := func(C <-chan time.Time) {
+		select {
+		case now := <-C:
+			t.Errorf("want no tick, got: t+%s", now.Sub(testTime))
+		default:
+		}
+	}
+
+	tc := m.NewTickerWithOptions(5*time.Second, clock.TickImmediately(), clock.MaxTicks(3))
+	test(tc.C, 0)
+	m.AddNext()
+	test(tc.C, 5*time.Second)
+	m.AddNext()
+	test(tc.C, 10*time.Second)
+	if got, want := m.Len(), 0; got != want {
+		t.Fatalf("want m.Len(): %d, got: %d", want, got)
+	}
+
+	td := m.NewTickerWithOptions(5*time.Second, clock.FixedDelay())
+	defer td.Stop()
+	m.Add(5 * time.Second)
+	m.Add(10 * time.Second) // previous tick not received yet
+	test(td.C, 15*time.Second)
+	none(td.C)
+	m.Add(time.Second) // next tick is measured from t+25s
+	none(td.C)
+	if got, want := m.Len(), 1; got != want {
+		t.Fatalf("want m.Len(): %d, got: %d", want, got)
+	}
+	_, d := m.AddNext()
+	if want := 4 * time.Second; d != want {
+		t.Fatalf("want AddNext(): %s, got: %s", want, d)
+	}
+	test(td.C, 30*time.Second)
+	td.Stop()
+	if got, want := m.Len(), 0; got != want {
+		t.Fatalf("want m.Len(): %d, got: %d", want, got)
+	}
+}
+
+func TestRealtime_NewTicker_options(t *testing.T) {
+	tc := clock.Realtime().NewTickerWithOptions(time.Millisecond, clock.TickImmediately(), clock.FixedDelay(), clock.MaxTicks(3))
+	defer tc.Stop()
+	var last time.Time
+	for i := 0; i < 3; i++ {
+		now := <-tc.C
+		if now.Before(last) {
+			t.Fatalf("want increasing ticks, got %s after %s", now, last)
+		}
+		last = now
+	}
+	select {
+	case <-tc.C:
+		t.Fatal("want no more than 3 ticks")
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestMock_NewJitterTicker(t *testing.T) {
+	const (
+		period = 10 * time.Second
+		jitter = 2 * time.Second
+	)
+	deadlines := func(seed int64) []time.Duration {
+		m := clock.NewMock(testTime)
+		m.Seed(seed)
+		tc := m.NewJitterTicker(period, jitter)
+		defer tc.Stop()
+		var ds []time.Duration
+		last := testTime
+		for i := 0; i < 20; i++ {
+			m.AddNext()
+			now := <-tc.C
+			if d := now.Sub(last); d < period-jitter || d > period+jitter {
+				t.Fatalf("want interval within %s±%s, got: %s", period, jitter, d)
+			}
+			ds = append(ds, now.Sub(last))
+			last = now
+		}
+		return ds
+	}
+	a, b := deadlines(1), deadlines(1)
+	same := true
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("want same intervals for same seed: %s, got: %s", a, b)
+		}
+		same = same && a[i] == a[0]
+	}
+	if same {
+		t.Fatalf("want randomized intervals, got: %s", a)
+	}
+}
+
+func ExampleMock_NewAlignedTicker() {
+	mock := clock.NewMock(time.Date(2018, 1, 1, 10, 0, 42, 0, time.UTC))
+	ticker := mock.NewAlignedTicker(time.Minute, 0)
+	defer ticker.Stop()
+	for i := 0; i < 3; i++ {
+		mock.AddNext()
+		fmt.Println("Flush at", <-ticker.C)
+	}
+	// Output:
+	// Flush at 2018-01-01 10:01:00 +0000 UTC
+	// Flush at 2018-01-01 10:02:00 +0000 UTC
+	// Flush at 2018-01-01 10:03:00 +0000 UTC
+}
@@ -0,0 +1,204 @@
+package clock
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// TimelineEvent is an event of a Mock timer or ticker, recorded with
+// WithTimeline.
+type TimelineEvent struct {
+	Time     time.Time // the current time of the Mock
+	Timer    int       // the timer number, starting from 1 in creation order
+	Kind     string    // "Timer", "AfterFunc" or "Ticker"
+	Event    string    // "created", "reset", "stopped", "fired", "paused" or "resumed"
+	Deadline time.Time // the new deadline of created, reset and resumed events
+	Site     string    // the file:line where the timer was created
+}
+
+// Timeline is a list of TimelineEvents in the order they happened.
+type Timeline []TimelineEvent
+
+// WithTimeline makes the Mock record a Timeline of the activity of its
+// timers and tickers, including those of sleeps and deadline contexts.
+// See Mock.Timeline.
+func WithTimeline() MockOption {
+	return func(m *Mock) {
+		m.timeline = &timelineRecorder{}
+	}
+}
+
+type timelineRecorder struct {
+	events Timeline
+	timers int
+}
+
+// Timeline returns the events recorded by a Mock created with WithTimeline.
+func (m *Mock) Timeline() Timeline {
+	m.lock()
+	defer m.Unlock()
+	if m.timeline == nil {
+		return nil
+	}
+	return append(Timeline(nil), m.timeline.events...)
+}
+
+// created records the creation of t, if the timeline is enabled.
+// The mock must be locked.
+func (m *Mock) created(t *mockTimer, kind string) {
+	if m.timeline == nil {
+		return
+	}
+	m.timeline.timers++
+	t.id, t.kind, t.site = m.timeline.timers, kind, callerSite()
+	m.record(t, "created")
+}
+
+// record records an event of t, if the timeline is enabled.
+// The mock must be locked.
+func (m *Mock) record(t *mockTimer, event string) {
+	if m.timeline == nil {
+		return
+	}
+	e := TimelineEvent{
+		Time:  m.wall(),
+		Timer: t.id,
+		Kind:  t.kind,
+		Event: event,
+		Site:  t.site,
+	}
+	switch event {
+	case "created", "reset", "resumed":
+		e.Deadline = m.wallAt(t.deadline)
+	}
+	m.timeline.events = append(m.timeline.events, e)
+}
+
+var pkgPrefix = reflect.TypeOf(Mock{}).PkgPath() + "."
+
+// callerSite returns the file:line of the first caller outside this package.
+func callerSite() string {
+	var pcs [16]uintptr
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs[:])])
+	for {
+		f, more := frames.Next()
+		if !strings.HasPrefix(f.Function, pkgPrefix) {
+			return fmt.Sprintf("%s:%d", shortFile(f.File), f.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+func shortFile(file string) string {
+	if i := strings.LastIndex(file, "/"); i >= 0 {
+		if j := strings.LastIndex(file[:i], "/"); j >= 0 {
+			return file[j+1:]
+		}
+	}
+	return file
+}
+
+// WriteTable writes the timeline as a plain text table.
+func (tl Timeline) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "TIME\tTIMER\tKIND\tEVENT\tDEADLINE\tSITE")
+	for _, e := range tl {
+		deadline := "-"
+		if !e.Deadline.IsZero() {
+			deadline = e.Deadline.Format(time.RFC3339Nano)
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%s\t%s\n",
+			e.Time.Format(time.RFC3339Nano), e.Timer, e.Kind, e.Event, deadline, e.Site)
+	}
+	return tw.Flush()
+}
+
+// String returns the timeline as a plain text table.
+func (tl Timeline) String() string {
+	var b bytes.Buffer
+	tl.WriteTable(&b)
+	return b.String()
+}
+
+type traceEvent struct {
+	Name  string                 `json:"name"`
+	Cat   string                 `json:"cat,omitempty"`
+	Phase string                 `json:"ph"`
+	Scope string                 `json:"s,omitempty"`
+	TS    float64                `json:"ts"`
+	Dur   float64                `json:"dur,omitempty"`
+	PID   int                    `json:"pid"`
+	TID   int                    `json:"tid"`
+	Args  map[string]interface{} `json:"args,omitempty"`
+}
+
+// WriteChromeTrace writes the timeline in the Chrome Trace Event JSON
+// format, which can be opened with chrome://tracing or Perfetto. Each timer
+// is shown on its own track, with the waits between arming and expiring
+// the timer as slices and the events as instants. The timestamps are
+// relative to the first event.
+func (tl Timeline) WriteChromeTrace(w io.Writer) error {
+	events := []traceEvent{}
+	if len(tl) > 0 {
+		start := tl[0].Time
+		ts := func(t time.Time) float64 {
+			return float64(t.Sub(start)) / float64(time.Microsecond)
+		}
+		armed := map[int]TimelineEvent{}
+		for _, e := range tl {
+			if e.Event == "created" {
+				events = append(events, traceEvent{
+					Name:  "thread_name",
+					Phase: "M",
+					PID:   1,
+					TID:   e.Timer,
+					Args:  map[string]interface{}{"name": fmt.Sprintf("%s %d %s", e.Kind, e.Timer, e.Site)},
+				})
+			}
+			if a, ok := armed[e.Timer]; ok {
+				events = append(events, traceEvent{
+					Name:  "wait",
+					Cat:   a.Kind,
+					Phase: "X",
+					TS:    ts(a.Time),
+					Dur:   ts(e.Time) - ts(a.Time),
+					PID:   1,
+					TID:   e.Timer,
+				})
+				delete(armed, e.Timer)
+			}
+			switch {
+			case e.Event == "created", e.Event == "reset", e.Event == "resumed",
+				e.Event == "fired" && e.Kind == "Ticker":
+				armed[e.Timer] = e
+			}
+			args := map[string]interface{}{"time": e.Time.Format(time.RFC3339Nano)}
+			if !e.Deadline.IsZero() {
+				args["deadline"] = e.Deadline.Format(time.RFC3339Nano)
+			}
+			events = append(events, traceEvent{
+				Name:  e.Event,
+				Cat:   e.Kind,
+				Phase: "i",
+				Scope: "t",
+				TS:    ts(e.Time),
+				PID:   1,
+				TID:   e.Timer,
+				Args:  args,
+			})
+		}
+	}
+	return json.NewEncoder(w).Encode(struct {
+		TraceEvents     []traceEvent `json:"traceEvents"`
+		DisplayTimeUnit string       `json:"displayTimeUnit"`
+	}{events, "ms"})
+}
@@ -0,0 +1,97 @@
+package clock_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tilinna/clock"
+)
+
+func TestMock_Timeline(t *testing.T) {
+	m := clock.NewMock(testTime, clock.WithTimeline())
+	timer := m.NewTimer(time.Second)
+	_, file, timerLine, _ := runtime.Caller(0)
+	ticker := m.NewTicker(2 * time.Second)
+	_, _, tickerLine, _ := runtime.Caller(0)
+	sites := map[int]string{
+		1: fmt.Sprintf("%s:%d", filepath.Base(file), timerLine-1),
+		2: fmt.Sprintf("%s:%d", filepath.Base(file), tickerLine-1),
+	}
+	m.Add(2 * time.Second)
+	timer.Reset(time.Second)
+	timer.Stop()
+	ticker.Stop()
+
+	type event struct {
+		at    time.Duration
+		timer int
+		event string
+	}
+	want := []event{
+		{0, 1, "created"},
+		{0, 2, "created"},
+		{time.Second, 1, "fired"},
+		{2 * time.Second, 2, "fired"},
+		{2 * time.Second, 1, "reset"},
+		{2 * time.Second, 1, "stopped"},
+		{2 * time.Second, 2, "stopped"},
+	}
+	tl := m.Timeline()
+	if len(tl) != len(want) {
+		t.Fatalf("want %d events, got:\n%s", len(want), tl)
+	}
+	for i, e := range tl {
+		if got := (event{e.Time.Sub(testTime), e.Timer, e.Event}); got != want[i] {
+			t.Errorf("want event %d: %v, got: %v", i, want[i], got)
+		}
+		if want := sites[e.Timer]; !strings.HasSuffix(e.Site, "/"+want) {
+			t.Errorf("want Site %q, got: %q", want, e.Site)
+		}
+	}
+	if got, want := tl[4].Deadline, testTime.Add(3*time.Second); !got.Equal(want) {
+		t.Errorf("want reset Deadline: %v, got: %v", want, got)
+	}
+	if !strings.HasPrefix(tl.String(), "TIME ") {
+		t.Errorf("want table header, got:\n%s", tl)
+	}
+
+	var buf bytes.Buffer
+	if err := tl.WriteChromeTrace(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var trace struct {
+		TraceEvents []struct {
+			Name  string  `json:"name"`
+			Phase string  `json:"ph"`
+			TS    float64 `json:"ts"`
+			Dur   float64 `json:"dur"`
+			TID   int     `json:"tid"`
+		} `json:"traceEvents"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &trace); err != nil {
+		t.Fatal(err)
+	}
+	var waits int
+	for _, e := range trace.TraceEvents {
+		if e.Phase == "X" {
+			waits++
+			if e.TID == 1 && e.TS == 0 && e.Dur != 1e6 {
+				t.Errorf("want first wait of 1s, got: %vµs", e.Dur)
+			}
+		}
+	}
+	// Timer: created-fired, reset-stopped. Ticker: created-fired, fired-stopped.
+	if waits != 4 {
+		t.Errorf("want 4 waits, got: %d", waits)
+	}
+
+	if tl := clock.NewMock(testTime).Timeline(); tl != nil {
+		t.Errorf("want no Timeline, got: %v", tl)
+	}
+}
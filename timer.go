@@ -1,11 +1,15 @@
 package clock
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 // Timer represents a time.Timer.
 type Timer struct {
 	C     <-chan time.Time
 	timer *time.Timer
+	state *realtimeState
 	*mockTimer
 }
 
@@ -17,26 +21,54 @@ func (m *Mock) After(d time.Duration) <-chan time.Time {
 	return m.NewTimer(d).C
 }
 
+// AfterAt waits until the time t and then sends the current time on the
+// returned channel.
+//
+// A time t not after the current time fires the underlying timer immediately.
+func (m *Mock) AfterAt(t time.Time) <-chan time.Time {
+	return m.NewTimerAt(t).C
+}
+
 // AfterFunc waits for the duration to elapse and then calls f in its own goroutine.
 // It returns a Timer that can be used to cancel the call using its Stop method.
 //
 // A negative or zero duration fires the timer immediately.
 func (m *Mock) AfterFunc(d time.Duration, f func()) *Timer {
-	m.Lock()
+	m.lock()
 	defer m.Unlock()
 	return m.newTimerFunc(m.now.Add(d), f)
 }
 
+// AfterFuncAt waits until the time t and then calls f in its own goroutine.
+// It returns a Timer that can be used to cancel the call using its Stop method.
+//
+// A time t not after the current time fires the timer immediately.
+func (m *Mock) AfterFuncAt(t time.Time, f func()) *Timer {
+	m.lock()
+	defer m.Unlock()
+	return m.newTimerFunc(m.internal(t), f)
+}
+
 // NewTimer creates a new Timer that will send the current time on its channel
 // after at least duration d.
 //
 // A negative or zero duration fires the timer immediately.
 func (m *Mock) NewTimer(d time.Duration) *Timer {
-	m.Lock()
+	m.lock()
 	defer m.Unlock()
 	return m.newTimerFunc(m.now.Add(d), nil)
 }
 
+// NewTimerAt creates a new Timer that will send the current time on its
+// channel at time t.
+//
+// A time t not after the current time fires the timer immediately.
+func (m *Mock) NewTimerAt(t time.Time) *Timer {
+	m.lock()
+	defer m.Unlock()
+	return m.newTimerFunc(m.internal(t), nil)
+}
+
 // Sleep pauses the current goroutine for at least the duration d.
 //
 // A negative or zero duration causes Sleep to return immediately.
@@ -44,21 +76,51 @@ func (m *Mock) Sleep(d time.Duration) {
 	<-m.After(d)
 }
 
+// SleepContext pauses the current goroutine for at least the duration d,
+// or until the context is done, in which case ctx.Err() is returned and
+// the underlying timer is stopped.
+//
+// A negative or zero duration causes SleepContext to return immediately.
+func (m *Mock) SleepContext(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	t := m.NewTimer(d)
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		t.Stop()
+		return ctx.Err()
+	}
+}
+
+// SleepUntil pauses the current goroutine at least until the time t.
+//
+// A time t not after the current time causes SleepUntil to return immediately.
+func (m *Mock) SleepUntil(t time.Time) {
+	<-m.AfterAt(t)
+}
+
 func (m *Mock) newTimerFunc(deadline time.Time, afterFunc func()) *Timer {
 	t := &Timer{
 		mockTimer: newMockTimer(m, deadline),
 	}
 	if afterFunc != nil {
+		m.created(t.mockTimer, "AfterFunc")
 		t.fire = func() time.Duration {
+			m.record(t.mockTimer, "fired")
 			go afterFunc()
 			return 0
 		}
 	} else {
+		m.created(t.mockTimer, "Timer")
 		c := make(chan time.Time, 1)
 		t.C = c
 		t.fire = func() time.Duration {
+			m.record(t.mockTimer, "fired")
 			select {
-			case c <- m.now:
+			case c <- m.wall():
 			default:
 			}
 			return 0
@@ -75,14 +137,25 @@ func (m *Mock) newTimerFunc(deadline time.Time, afterFunc func()) *Timer {
 // Stop prevents the Timer from firing.
 // It returns true if the call stops the timer, false if the timer has already
 // expired or been stopped.
+//
+// A paused timer is considered active.
 func (t *Timer) Stop() bool {
 	if t.timer != nil {
-		return t.timer.Stop()
+		s := t.state
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		wasPaused := s.paused
+		s.deadline, s.active, s.paused = time.Time{}, false, false
+		return t.timer.Stop() || wasPaused
 	}
-	t.mock.Lock()
+	t.mock.lock()
 	defer t.mock.Unlock()
-	wasActive := !t.mockTimer.stopped()
+	wasActive := !t.mockTimer.stopped() || t.paused
+	t.paused = false
 	t.mock.stop(t.mockTimer)
+	if wasActive {
+		t.mock.record(t.mockTimer, "stopped")
+	}
 	return wasActive
 }
 
@@ -90,15 +163,24 @@ func (t *Timer) Stop() bool {
 // It returns true if the timer had been active, false if the timer had
 // expired or been stopped.
 //
+// A paused timer is considered active, and is resumed by Reset.
+//
 // A negative or zero duration fires the timer immediately.
 func (t *Timer) Reset(d time.Duration) bool {
 	if t.timer != nil {
-		return t.timer.Reset(d)
+		s := t.state
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		wasPaused := s.paused
+		s.deadline, s.active, s.paused = time.Now().Add(d), true, false
+		return t.timer.Reset(d) || wasPaused
 	}
-	t.mock.Lock()
+	t.mock.lock()
 	defer t.mock.Unlock()
-	wasActive := !t.mockTimer.stopped()
+	wasActive := !t.mockTimer.stopped() || t.paused
+	t.paused = false
 	t.deadline = t.mock.now.Add(d)
+	t.mock.record(t.mockTimer, "reset")
 	if !t.deadline.After(t.mock.now) {
 		t.fire()
 		t.mock.stop(t.mockTimer)
@@ -107,3 +189,112 @@ func (t *Timer) Reset(d time.Duration) bool {
 	}
 	return wasActive
 }
+
+// Deadline returns the time when the timer will expire.
+// It returns false if the timer has already expired, been stopped or paused.
+func (t *Timer) Deadline() (time.Time, bool) {
+	if t.state != nil {
+		return t.state.deadlineOf()
+	}
+	t.mock.lock()
+	defer t.mock.Unlock()
+	return t.mockDeadline()
+}
+
+// Remaining returns the duration until the timer expires, or the remaining
+// duration of a paused timer.
+// It returns zero if the timer has already expired or been stopped.
+func (t *Timer) Remaining() time.Duration {
+	if t.state != nil {
+		return t.state.remaining()
+	}
+	t.mock.lock()
+	defer t.mock.Unlock()
+	return t.mockRemaining()
+}
+
+// Active reports whether the timer has not yet expired or been stopped.
+// A paused timer is considered active.
+func (t *Timer) Active() bool {
+	if t.state != nil {
+		_, active := t.state.get()
+		return active
+	}
+	t.mock.lock()
+	defer t.mock.Unlock()
+	return t.mockActive()
+}
+
+// Pause stops the timer from counting down, preserving its remaining
+// duration until Resume is called.
+// It returns true if the call pauses the timer, false if the timer has
+// already expired, been stopped or paused.
+func (t *Timer) Pause() bool {
+	if t.timer != nil {
+		s := t.state
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if !s.active || s.paused {
+			return false
+		}
+		remaining := time.Until(s.deadline)
+		if !t.timer.Stop() {
+			return false
+		}
+		if remaining < 0 {
+			remaining = 0
+		}
+		s.deadline, s.paused, s.pausedRemaining = time.Time{}, true, remaining
+		return true
+	}
+	t.mock.lock()
+	defer t.mock.Unlock()
+	if t.stopped() {
+		return false
+	}
+	t.paused = true
+	t.pausedRemaining = t.deadline.Sub(t.mock.now)
+	t.mock.stop(t.mockTimer)
+	t.mock.record(t.mockTimer, "paused")
+	return true
+}
+
+// Resume continues a paused timer with the remaining duration it had when
+// it was paused.
+// It returns true if the call resumes the timer, false if the timer was not
+// paused.
+func (t *Timer) Resume() bool {
+	if t.timer != nil {
+		s := t.state
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if !s.paused {
+			return false
+		}
+		s.deadline, s.paused = time.Now().Add(s.pausedRemaining), false
+		t.timer.Reset(s.pausedRemaining)
+		return true
+	}
+	t.mock.lock()
+	defer t.mock.Unlock()
+	if !t.paused {
+		return false
+	}
+	t.paused = false
+	t.deadline = t.mock.now.Add(t.pausedRemaining)
+	t.mock.start(t.mockTimer)
+	t.mock.record(t.mockTimer, "resumed")
+	return true
+}
+
+// Paused reports whether the timer is paused.
+func (t *Timer) Paused() bool {
+	if t.timer != nil {
+		t.state.mu.Lock()
+		defer t.state.mu.Unlock()
+		return t.state.paused
+	}
+	t.mock.lock()
+	defer t.mock.Unlock()
+	return t.paused
+}
@@ -0,0 +1,90 @@
+//go:build go1.10
+// +build go1.10
+
+package clock
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"time"
+)
+
+// Zone describes a time zone which is in effect from Start until the Start
+// of the next Zone.
+type Zone struct {
+	Start  time.Time
+	Name   string
+	Offset int // seconds east of UTC
+	IsDST  bool
+}
+
+// SimulatedLocation returns a Location with the given zones, which must be
+// in the order of their Start times. The first zone is in effect also
+// before its Start.
+//
+// Use it with Mock.SetLocation to test daylight saving time transitions and
+// changes to the time zone rules on specific dates, independently of the
+// time zone database of the system. The Start times must be between the
+// years 1901 and 2038.
+func SimulatedLocation(name string, zones ...Zone) (*time.Location, error) {
+	if len(zones) == 0 {
+		return nil, errors.New("clock: no zones for SimulatedLocation")
+	}
+	if len(zones) > math.MaxUint8 {
+		return nil, errors.New("clock: too many zones for SimulatedLocation")
+	}
+	var (
+		times []byte
+		chars []byte
+		infos []byte
+	)
+	for i, z := range zones {
+		if i > 0 {
+			sec := z.Start.Unix()
+			if sec < math.MinInt32 || sec > math.MaxInt32 {
+				return nil, errors.New("clock: zone start out of range for SimulatedLocation")
+			}
+			if i > 1 && !zones[i-1].Start.Before(z.Start) {
+				return nil, errors.New("clock: zones out of order for SimulatedLocation")
+			}
+			times = appendUint32(times, uint32(int32(sec)))
+		}
+		info := appendUint32(nil, uint32(int32(z.Offset)))
+		dst := byte(0)
+		if z.IsDST {
+			dst = 1
+		}
+		info = append(info, dst, byte(len(chars)))
+		infos = append(infos, info...)
+		chars = append(chars, z.Name...)
+		chars = append(chars, 0)
+	}
+	if len(chars) > math.MaxUint8 {
+		return nil, errors.New("clock: zone names too long for SimulatedLocation")
+	}
+
+	// The data is in the version 1 TZif format described in RFC 8536.
+	// The first zone is never transitioned to, which makes it the zone
+	// in effect before the first transition.
+	var b bytes.Buffer
+	b.WriteString("TZif")
+	b.Write(make([]byte, 16)) // version 1 and reserved bytes
+	for _, n := range []int{0, 0, 0, len(zones) - 1, len(zones), len(chars)} {
+		b.Write(appendUint32(nil, uint32(n)))
+	}
+	b.Write(times)
+	for i := 1; i < len(zones); i++ {
+		b.WriteByte(byte(i))
+	}
+	b.Write(infos)
+	b.Write(chars)
+	return time.LoadLocationFromTZData(name, b.Bytes())
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], v)
+	return append(b, buf[:]...)
+}
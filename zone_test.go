@@ -0,0 +1,44 @@
+//go:build go1.10
+// +build go1.10
+
+package clock_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tilinna/clock"
+)
+
+func TestMock_SetLocation(t *testing.T) {
+	dst := testTime.Add(time.Hour)
+	loc, err := clock.SimulatedLocation("Test/DST",
+		clock.Zone{Name: "TST", Offset: 2 * 3600},
+		clock.Zone{Start: dst, Name: "TDT", Offset: 3 * 3600, IsDST: true},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := clock.NewMock(testTime, clock.WithLocation(loc))
+	tm := m.NewTimer(2 * time.Hour)
+	if got, want := m.Now().Format("15:04 MST"), "12:00 TST"; got != want {
+		t.Fatalf("want m.Now(): %s, got: %s", want, got)
+	}
+	if got, want := m.Add(time.Hour).Format("15:04 MST"), "14:00 TDT"; got != want {
+		t.Fatalf("want m.Add(): %s, got: %s", want, got)
+	}
+	m.Add(time.Hour)
+	if got, want := (<-tm.C).Format("15:04 MST"), "15:00 TDT"; got != want {
+		t.Fatalf("want timeout at %s, got: %s", want, got)
+	}
+
+	m.SetLocation(time.UTC)
+	if got, want := m.Now(), testTime.Add(2*time.Hour); !got.Equal(want) {
+		t.Fatalf("want m.Now(): %s, got: %s", want, got)
+	}
+	m.SetLocation(nil)
+	if got, want := m.Now().Location(), testTime.Location(); got != want {
+		t.Fatalf("want m.Now().Location(): %s, got: %s", want, got)
+	}
+}